@@ -0,0 +1,90 @@
+package sneterr
+
+import "errors"
+
+// An AccessKind tells which kind of access to a storage failed, so
+// degradation logic can keep serving reads while only writes fail.
+type AccessKind int
+
+// Kinds of access. The zero value means the kind is not known.
+const (
+	AccessUnset AccessKind = iota
+	AccessRead
+	AccessWrite
+	AccessAdmin
+)
+
+// FieldAccess is the field key of the kind of access of an error, set by
+// WithAccess.
+const FieldAccess = "access"
+
+var accessNames = [...]string{
+	AccessUnset: "unset",
+	AccessRead:  "read",
+	AccessWrite: "write",
+	AccessAdmin: "admin",
+}
+
+// String returns the lower case name of the kind of access.
+func (a AccessKind) String() string {
+	if a < 0 || int(a) >= len(accessNames) {
+		return "unknown"
+	}
+	return accessNames[a]
+}
+
+// ParseAccess returns the kind of access named s, as returned by String,
+// and AccessUnset if there is none.
+func ParseAccess(s string) AccessKind {
+	for a, name := range accessNames {
+		if name == s {
+			return AccessKind(a)
+		}
+	}
+	return AccessUnset
+}
+
+// WithAccess records the kind of access which failed in the FieldAccess
+// field, overriding the one registered for the code. It suits codes such
+// as a permission error which are reported by reads and writes alike.
+func WithAccess(a AccessKind) Option {
+	return WithField(FieldAccess, a.String())
+}
+
+// Access returns the kind of access which failed with err.
+//
+// The FieldAccess field set by WithAccess is used first. Otherwise an
+// error of the chain implementing Access() AccessKind reports it, or the
+// kind registered for the code of the first error of the chain which has
+// one. A nil error, or one of unknown access, has AccessUnset.
+func Access(err error) AccessKind {
+	if err == nil {
+		return AccessUnset
+	}
+	if name, ok := Field[string](err, FieldAccess); ok {
+		if a := ParseAccess(name); a != AccessUnset {
+			return a
+		}
+	}
+
+	var r interface{ Access() AccessKind }
+	if errors.As(err, &r) {
+		if a := r.Access(); a != AccessUnset {
+			return a
+		}
+	}
+
+	access := AccessUnset
+	walk(err, func(e error) bool {
+		se, ok := e.(Error)
+		if !ok {
+			return true
+		}
+		if info, ok := Lookup(se.Code()); ok && info.Access != AccessUnset {
+			access = info.Access
+			return false
+		}
+		return true
+	})
+	return access
+}
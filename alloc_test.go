@@ -0,0 +1,11 @@
+package sneterr_test
+
+import (
+	"testing"
+
+	"github.com/servicenetjp/sneterr/sneterrbench"
+)
+
+func TestAllocs(t *testing.T) {
+	sneterrbench.CheckAllocs(t)
+}
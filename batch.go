@@ -0,0 +1,51 @@
+package sneterr
+
+import "net/http"
+
+// ErrCodeBatch is the code of the MultiError returned by NewBatchError.
+const ErrCodeBatch = "BatchFailed"
+
+// Field keys locating the errors grouped by a batch error in the input,
+// such as a row and column of an imported spreadsheet.
+const (
+	FieldRow    = "row"
+	FieldColumn = "column"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodeBatch,
+		Category: CategoryInvalidArgument,
+		Severity: SeverityInfo,
+		Status:   http.StatusUnprocessableEntity,
+	})
+}
+
+// NewFieldError returns an Error with code and message for the value of
+// column in row of an input, such as a file being imported. Rows are
+// numbered from 1; column may be empty when the whole row is at fault.
+func NewFieldError(row int, column, code, message string, opts ...Option) Error {
+	b := newBaseError(code, message, nil, "", 0)
+	b.fields = Fields{FieldRow: row}
+	if column != "" {
+		b.fields[FieldColumn] = column
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// Position returns the row and column of the input err is about, as set
+// by NewFieldError, including when err was decoded from JSON.
+func Position(err error) (row int, column string, ok bool) {
+	row, ok = Field[int](err, FieldRow)
+	column, _ = Field[string](err, FieldColumn)
+	return row, column, ok
+}
+
+// NewBatchError returns a MultiError with ErrCodeBatch grouping the errors
+// found while processing a batch, typically built with NewFieldError. Its
+// JSON encoding lists each error with its position, for clients to point
+// at the faulty values.
+func NewBatchError(message string, errs []error) MultiError {
+	return NewMultiError(ErrCodeBatch, message, errs)
+}
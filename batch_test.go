@@ -0,0 +1,25 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPosition(t *testing.T) {
+	err := NewFieldError(3, "email", "InvalidEmail", "invalid email")
+	data, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatal(mErr)
+	}
+	decoded, uErr := UnmarshalError(data)
+	if uErr != nil {
+		t.Fatal(uErr)
+	}
+
+	for name, err := range map[string]error{"local": err, "decoded": decoded} {
+		row, column, ok := Position(err)
+		if !ok || row != 3 || column != "email" {
+			t.Errorf("%s: Position() = %d, %q, %v, want 3, email, true", name, row, column, ok)
+		}
+	}
+}
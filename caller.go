@@ -0,0 +1,125 @@
+package sneterr
+
+import (
+	"path"
+	"runtime"
+	"sync"
+)
+
+var (
+	helpersMu sync.RWMutex
+	helpers   = map[string]struct{}{}
+)
+
+// Helper marks the calling function as an error helper. Errors created
+// inside a helper, directly or through other helpers, report the location
+// of the first caller which is not a helper, in the spirit of
+// testing.T.Helper:
+//
+//	func notFound(id string) sneterr.Error {
+//		sneterr.Helper()
+//		return sneterr.New("NotFound", id+" not found")
+//	}
+func Helper() {
+	var pc [1]uintptr
+	if runtime.Callers(2, pc[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pc[:]).Next()
+
+	helpersMu.RLock()
+	_, ok := helpers[frame.Function]
+	helpersMu.RUnlock()
+	if ok {
+		return
+	}
+	helpersMu.Lock()
+	helpers[frame.Function] = struct{}{}
+	helpersMu.Unlock()
+}
+
+// NewWithSkip is like New but reports the location skip frames above its
+// caller. NewWithSkip(0, ...) is the same as New.
+func NewWithSkip(skip int, code, message string, opts ...Option) Error {
+	return newError(skip+2, code, message, nil, opts...)
+}
+
+// maxDebugFrames bounds the stacks captured for debug errors, see
+// WithDebug.
+const maxDebugFrames = 512
+
+// callers returns the program counters of the stack starting skip frames
+// above the caller of callers.
+func callers(skip int) []uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// allCallers is like callers but captures up to maxDebugFrames frames.
+func allCallers(skip int) []uintptr {
+	pcs := make([]uintptr, maxDebugFrames)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n:n]
+}
+
+// frames returns up to n frames of the stack pcs, leaving out the leading
+// frames of functions marked by Helper. If every frame belongs to a helper
+// the stack is returned from its first frame.
+func frames(pcs []uintptr, n int) []runtime.Frame {
+	if len(pcs) == 0 || n <= 0 {
+		return nil
+	}
+
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+
+	var all, out []runtime.Frame
+	it := runtime.CallersFrames(pcs)
+	for {
+		frame, more := it.Next()
+		if _, ok := helpers[frame.Function]; !ok || len(out) > 0 {
+			out = append(out, frame)
+			if len(out) == n {
+				return out
+			}
+		}
+		if len(all) < n {
+			all = append(all, frame)
+		}
+		if !more {
+			break
+		}
+	}
+	if len(out) == 0 {
+		return all
+	}
+	return out
+}
+
+// trimHelpers returns the stack pcs without its leading frames of
+// functions marked by Helper.
+func trimHelpers(pcs []uintptr) []uintptr {
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+
+	for len(pcs) > 0 {
+		frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+		if _, ok := helpers[frame.Function]; !ok {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	return pcs
+}
+
+// location returns the file name and line of the first frame of the stack
+// pcs which does not belong to a helper.
+func location(pcs []uintptr) (string, int) {
+	fs := frames(pcs, 1)
+	if len(fs) == 0 {
+		return "", 0
+	}
+	_, file := path.Split(fs[0].File)
+	return file, fs[0].Line
+}
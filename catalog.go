@@ -0,0 +1,106 @@
+package sneterr
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CodeInfo describes an error code registered in the catalog.
+type CodeInfo struct {
+	// The error code being described.
+	Code string
+
+	// Category of errors with this code.
+	Category Category
+
+	// Severity of errors with this code. SeverityError is assumed when
+	// unset.
+	Severity Severity
+
+	// Types of the fields of errors with this code, checked in dev mode.
+	Fields Schema
+
+	// Whether a failed operation reporting this code can be retried.
+	Retryable bool
+
+	// Kind of storage access failing with this code, if the code is
+	// specific to one.
+	Access AccessKind
+
+	// HTTP status code of responses for errors with this code.
+	// http.StatusInternalServerError is assumed when unset.
+	Status int
+
+	// What operators can do about errors with this code, for Summarize.
+	Remediation string
+
+	// Links to the documentation or runbooks of the code, for Summarize.
+	Links []string
+
+	// When the code was deprecated, if it was.
+	DeprecatedSince time.Time
+
+	// When the deprecated code stops being served, if known.
+	Sunset time.Time
+}
+
+// Deprecated reports whether the code was deprecated.
+func (info CodeInfo) Deprecated() bool {
+	return !info.DeprecatedSince.IsZero() || !info.Sunset.IsZero()
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]CodeInfo{}
+)
+
+// Register adds info to the catalog, replacing any previous entry for the
+// same code.
+func Register(info CodeInfo) {
+	catalogMu.Lock()
+	catalog[info.Code] = info
+	catalogMu.Unlock()
+}
+
+// Lookup returns the catalog entry registered for code.
+func Lookup(code string) (CodeInfo, bool) {
+	catalogMu.RLock()
+	info, ok := catalog[code]
+	catalogMu.RUnlock()
+	return info, ok
+}
+
+// FieldRetryable is the field key of the retryability of an error, set by
+// WithRetryable.
+const FieldRetryable = "retryable"
+
+// WithRetryable records in the FieldRetryable field whether the failed
+// operation can be retried, overriding the catalog entry of the code. It
+// suits codes whose retryability depends on the failure, such as a write
+// timeout which may or may not have been applied.
+func WithRetryable(retryable bool) Option {
+	return WithField(FieldRetryable, retryable)
+}
+
+// Retryable reports whether the operation which failed with err can be
+// retried. An error implementing Retryable() bool decides for itself,
+// otherwise the FieldRetryable field set by WithRetryable or the catalog
+// entry of its code does. Errors with unregistered codes are not
+// retryable.
+func Retryable(err error) bool {
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	if retryable, ok := Field[bool](err, FieldRetryable); ok {
+		return retryable
+	}
+
+	var e Error
+	if errors.As(err, &e) {
+		info, _ := Lookup(e.Code())
+		return info.Retryable
+	}
+	return false
+}
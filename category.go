@@ -0,0 +1,198 @@
+package sneterr
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sync"
+)
+
+// A Category is a broad classification of errors shared by many codes,
+// such as every code reporting a missing resource.
+type Category string
+
+// Categories of errors. The value of a category is also the code given to
+// foreign errors converted by AsCategory.
+const (
+	CategoryUnknown            Category = "Unknown"
+	CategoryInvalidArgument    Category = "InvalidArgument"
+	CategoryUnauthenticated    Category = "Unauthenticated"
+	CategoryPermissionDenied   Category = "PermissionDenied"
+	CategoryNotFound           Category = "NotFound"
+	CategoryConflict           Category = "Conflict"
+	CategoryPreconditionFailed Category = "PreconditionFailed"
+	CategoryResourceExhausted  Category = "ResourceExhausted"
+	CategoryTimeout            Category = "Timeout"
+	CategoryCanceled           Category = "Canceled"
+	CategoryUnavailable        Category = "Unavailable"
+	CategoryInternal           Category = "Internal"
+)
+
+// categoryStatus maps categories to the HTTP status used for codes which
+// have none registered.
+var categoryStatus = map[Category]int{
+	CategoryInvalidArgument:    http.StatusBadRequest,
+	CategoryUnauthenticated:    http.StatusUnauthorized,
+	CategoryPermissionDenied:   http.StatusForbidden,
+	CategoryNotFound:           http.StatusNotFound,
+	CategoryConflict:           http.StatusConflict,
+	CategoryPreconditionFailed: http.StatusPreconditionFailed,
+	CategoryResourceExhausted:  http.StatusTooManyRequests,
+	CategoryTimeout:            http.StatusGatewayTimeout,
+	CategoryCanceled:           499,
+	CategoryUnavailable:        http.StatusServiceUnavailable,
+	CategoryInternal:           http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status of responses for errors of the
+// category.
+func (c Category) HTTPStatus() int {
+	if s, ok := categoryStatus[c]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// A Categorizer classifies foreign errors, which are not an Error, such as
+// those of a database driver. It reports false for errors it does not
+// know.
+type Categorizer func(err error) (Category, bool)
+
+var (
+	categorizersMu sync.RWMutex
+	categorizers   = []Categorizer{stdCategorizer}
+)
+
+// RegisterCategorizer adds c to the categorizers consulted for foreign
+// errors, after those already registered. Errors of the standard library
+// for missing files, permissions and context ends are categorized by
+// default, as are network timeouts and the system error numbers known to
+// ErrnoCategory.
+func RegisterCategorizer(c Categorizer) {
+	categorizersMu.Lock()
+	categorizers = append(categorizers, c)
+	categorizersMu.Unlock()
+}
+
+// stdCategorizer categorizes errors of the standard library.
+func stdCategorizer(err error) (Category, bool) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return CategoryNotFound, true
+	case errors.Is(err, fs.ErrPermission):
+		return CategoryPermissionDenied, true
+	case errors.Is(err, fs.ErrExist):
+		return CategoryConflict, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return CategoryTimeout, true
+	case errors.Is(err, context.Canceled):
+		return CategoryCanceled, true
+	case isTimeout(err):
+		return CategoryTimeout, true
+	}
+	return errnoCategoryOf(err)
+}
+
+// categoryOf returns the category of err itself, without looking at the
+// errors it wraps.
+func categoryOf(err error) (Category, bool) {
+	if c, ok := err.(interface{ Category() Category }); ok {
+		if cat := c.Category(); cat != "" {
+			return cat, true
+		}
+	}
+	if e, ok := err.(Error); ok {
+		info, _ := Lookup(e.Code())
+		if info.Category != "" {
+			return info.Category, true
+		}
+		return "", false
+	}
+
+	categorizersMu.RLock()
+	cs := categorizers
+	categorizersMu.RUnlock()
+	for _, c := range cs {
+		if cat, ok := c(err); ok {
+			return cat, true
+		}
+	}
+	return "", false
+}
+
+// CategoryOf returns the category of the first error in the chain of err
+// which has one, either through a Category() Category method, the catalog
+// entry of its code, or a Categorizer. It returns CategoryUnknown if there
+// is none.
+//
+// Like CodeOf, CategoryOf does not allocate nor render messages, as long
+// as the Categorizer and Unwrap methods it calls do not.
+func CategoryOf(err error) Category {
+	cat := CategoryUnknown
+	walk(err, func(e error) bool {
+		if c, ok := categoryOf(e); ok {
+			cat = c
+			return false
+		}
+		return true
+	})
+	return cat
+}
+
+// AsCategory returns the first error in the chain of err with category
+// cat. A foreign error is returned wrapped in an Error with the category
+// as its code.
+func AsCategory(err error, cat Category) (Error, bool) {
+	var found Error
+	walk(err, func(e error) bool {
+		c, ok := categoryOf(e)
+		if !ok || c != cat {
+			return true
+		}
+		if se, ok := e.(Error); ok {
+			found = se
+		} else {
+			found = newBaseError(string(cat), e.Error(), e, "", 0)
+		}
+		return false
+	})
+	return found, found != nil
+}
+
+// AsRequestFailure returns the first RequestFailure in the chain of err.
+func AsRequestFailure(err error) (RequestFailure, bool) {
+	return As[RequestFailure](err)
+}
+
+// As is a generic errors.As, returning the first error in the chain of err
+// which is a T.
+func As[T error](err error) (T, bool) {
+	var t T
+	ok := errors.As(err, &t)
+	return t, ok
+}
+
+// walk calls fn on err and the errors it wraps, depth first, until fn
+// returns false.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if !fn(err) {
+			return false
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if !walk(e, fn) {
+					return false
+				}
+			}
+			return true
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return true
+		}
+	}
+	return true
+}
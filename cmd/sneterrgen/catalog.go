@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// A Catalog is the content of a catalog file.
+type Catalog struct {
+	Package string `json:"package" yaml:"package"`
+	Codes   []Code `json:"codes" yaml:"codes"`
+}
+
+// A Code describes an error code of the catalog.
+type Code struct {
+	Code        string            `json:"code" yaml:"code"`
+	Message     string            `json:"message" yaml:"message"`
+	Category    string            `json:"category" yaml:"category"`
+	Status      int               `json:"status" yaml:"status"`
+	Severity    string            `json:"severity" yaml:"severity"`
+	Retryable   bool              `json:"retryable" yaml:"retryable"`
+	Access      string            `json:"access" yaml:"access"`
+	Remediation string            `json:"remediation" yaml:"remediation"`
+	Links       []string          `json:"links" yaml:"links"`
+	Fields      map[string]string `json:"fields" yaml:"fields"`
+}
+
+// fieldTypes maps the field types of a catalog to their sneterr.FieldType
+// constant and Go type.
+var fieldTypes = map[string][2]string{
+	"":       {"FieldAny", "interface{}"},
+	"any":    {"FieldAny", "interface{}"},
+	"string": {"FieldString", "string"},
+	"int":    {"FieldInt", "int64"},
+	"float":  {"FieldFloat", "float64"},
+	"bool":   {"FieldBool", "bool"},
+	"time":   {"FieldTime", "time.Time"},
+}
+
+// severities maps the severities of a catalog to their sneterr constant.
+var severities = map[string]string{
+	"":         "",
+	"debug":    "SeverityDebug",
+	"info":     "SeverityInfo",
+	"warn":     "SeverityWarn",
+	"error":    "SeverityError",
+	"critical": "SeverityCritical",
+}
+
+// accesses maps the kinds of access of a catalog to their sneterr
+// constant.
+var accesses = map[string]string{
+	"":      "",
+	"read":  "AccessRead",
+	"write": "AccessWrite",
+	"admin": "AccessAdmin",
+}
+
+// model is the data given to the file template.
+type model struct {
+	Source     string
+	Package    string
+	Codes      []codeModel
+	Namespaces []namespaceModel
+	Getters    []fieldModel
+	UseTime    bool
+}
+
+type codeModel struct {
+	Code
+	Name     string
+	Const    string
+	Target   string
+	Severity string
+	Access   string
+	Params   []fieldModel
+}
+
+// namespaceModel describes the codes sharing a namespace, the part of
+// their code before the first dot, for the exhaustive switch helper.
+type namespaceModel struct {
+	Namespace string
+	Handler   string
+	Switch    string
+	Codes     []handledCode
+}
+
+type handledCode struct {
+	Const  string
+	Method string
+}
+
+type fieldModel struct {
+	Key       string
+	Name      string
+	Type      string
+	FieldType string
+}
+
+func newModel(c *Catalog, source string) (*model, error) {
+	if c.Package == "" {
+		return nil, fmt.Errorf("no package name, set it in the catalog or with -package")
+	}
+
+	m := &model{Source: source, Package: c.Package}
+	getters := map[string]fieldModel{}
+	consts := map[string]string{}
+	for _, code := range c.Codes {
+		if code.Code == "" {
+			return nil, fmt.Errorf("catalog entry without code")
+		}
+		sev, ok := severities[strings.ToLower(code.Severity)]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown severity %q", code.Code, code.Severity)
+		}
+
+		access, ok := accesses[strings.ToLower(code.Access)]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown access %q", code.Code, code.Access)
+		}
+
+		cm := codeModel{Code: code, Name: goName(code.Code), Severity: sev, Access: access}
+		cm.Const = "ErrCode" + cm.Name
+		cm.Target = "Err" + cm.Name
+		if prev, ok := consts[cm.Const]; ok {
+			return nil, fmt.Errorf("%s: generates the same names as %s", code.Code, prev)
+		}
+		consts[cm.Const] = code.Code
+
+		keys := make([]string, 0, len(code.Fields))
+		for k := range code.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			t, ok := fieldTypes[code.Fields[k]]
+			if !ok {
+				return nil, fmt.Errorf("%s: field %s has unknown type %q", code.Code, k, code.Fields[k])
+			}
+			f := fieldModel{Key: k, Name: goName(k), Type: t[1], FieldType: t[0]}
+			if prev, ok := getters[f.Name]; ok && prev.Type != f.Type {
+				return nil, fmt.Errorf("%s: field %s is %s, but %s elsewhere", code.Code, k, f.Type, prev.Type)
+			}
+			getters[f.Name] = f
+			cm.Params = append(cm.Params, f)
+			m.UseTime = m.UseTime || f.Type == "time.Time"
+		}
+		m.Codes = append(m.Codes, cm)
+	}
+
+	var err error
+	if m.Namespaces, err = namespaces(m.Codes); err != nil {
+		return nil, err
+	}
+
+	for _, c := range m.Codes {
+		if f, ok := getters[c.Target]; ok {
+			return nil, fmt.Errorf("%s: the getter of field %s has the name of its target %s", c.Code.Code, f.Key, c.Target)
+		}
+	}
+	for _, f := range getters {
+		m.Getters = append(m.Getters, f)
+	}
+	sort.Slice(m.Getters, func(i, j int) bool { return m.Getters[i].Name < m.Getters[j].Name })
+	return m, nil
+}
+
+// namespaces groups codes by namespace, in the order of their first code.
+func namespaces(codes []codeModel) ([]namespaceModel, error) {
+	var out []namespaceModel
+	index := map[string]int{}
+	methods := map[string]string{}
+	for _, c := range codes {
+		ns, name := splitNamespace(c.Code.Code)
+		i, ok := index[ns]
+		if !ok {
+			nm := namespaceModel{Namespace: ns, Handler: "CodeHandler", Switch: "SwitchCode"}
+			if ns != "" {
+				nm.Handler = goName(ns) + "Handler"
+				nm.Switch = "Switch" + goName(ns)
+			}
+			if prev, ok := methods[nm.Handler]; ok {
+				return nil, fmt.Errorf("namespace %q generates the same names as %q", ns, prev)
+			}
+			methods[nm.Handler] = ns
+			i = len(out)
+			index[ns] = i
+			out = append(out, nm)
+		}
+
+		method := "Handle" + goName(name)
+		key := out[i].Handler + "." + method
+		if prev, ok := methods[key]; ok {
+			return nil, fmt.Errorf("%s: generates the same handler method as %s", c.Code.Code, prev)
+		}
+		methods[key] = c.Code.Code
+		out[i].Codes = append(out[i].Codes, handledCode{Const: c.Const, Method: method})
+	}
+	return out, nil
+}
+
+// splitNamespace returns the namespace of code, the part before its first
+// dot, and the rest of the code.
+func splitNamespace(code string) (ns, name string) {
+	if i := strings.IndexByte(code, '.'); i > 0 && i < len(code)-1 {
+		return code[:i], code[i+1:]
+	}
+	return "", code
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "DB": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "SQL": true, "URL": true, "UUID": true,
+}
+
+// goName returns the exported Go name of a code or field key such as
+// "billing.card_declined" or "order_id".
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var sb strings.Builder
+	for _, w := range words {
+		if up := strings.ToUpper(w); initialisms[up] {
+			sb.WriteString(up)
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	name := sb.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// exhaustiveDirective marks a switch statement which must handle every
+// code of a namespace of the catalog. The directive is followed by a space
+// and the namespace, and sits on the line right above the switch:
+//
+//	//sneterr:exhaustive billing
+//	switch sneterr.CodeOf(err) {
+//	case billing.ErrCodeBillingCardDeclined:
+//	...
+//	}
+const exhaustiveDirective = "//sneterr:exhaustive"
+
+// check reports the switch statements of the Go files at paths marked
+// with exhaustiveDirective which do not handle every code of their
+// namespace in c. A path is a file, a directory, or a directory followed
+// by "/..." to include its subdirectories.
+func check(c *Catalog, paths []string) ([]string, error) {
+	m, err := newModel(c, "")
+	if err != nil {
+		return nil, err
+	}
+	consts := map[string]string{}
+	for _, code := range m.Codes {
+		consts[code.Const] = code.Code.Code
+	}
+	byNamespace := map[string][]string{}
+	for _, ns := range m.Namespaces {
+		for _, code := range ns.Codes {
+			byNamespace[ns.Namespace] = append(byNamespace[ns.Namespace], consts[code.Const])
+		}
+	}
+
+	files, err := goFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, checkFile(fset, f, consts, byNamespace)...)
+	}
+	return problems, nil
+}
+
+// A directive is an exhaustiveDirective found in a file.
+type directive struct {
+	namespace string
+	pos       token.Position
+	used      bool
+}
+
+// checkFile reports the marked switch statements of f missing codes, and
+// the directives which do not mark a switch statement.
+func checkFile(fset *token.FileSet, f *ast.File, consts map[string]string, byNamespace map[string][]string) []string {
+	directives := map[int]*directive{}
+	var order []*directive
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			rest, ok := strings.CutPrefix(c.Text, exhaustiveDirective)
+			if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+				continue
+			}
+			d := &directive{namespace: strings.TrimSpace(rest), pos: fset.Position(c.Slash)}
+			directives[d.pos.Line] = d
+			order = append(order, d)
+		}
+	}
+	if len(directives) == 0 {
+		return nil
+	}
+
+	var problems []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		sw, ok := n.(*ast.SwitchStmt)
+		if !ok {
+			return true
+		}
+		pos := fset.Position(sw.Pos())
+		d, ok := directives[pos.Line-1]
+		if !ok {
+			return true
+		}
+		d.used = true
+		codes, ok := byNamespace[d.namespace]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: no code in namespace %q", pos, d.namespace))
+			return true
+		}
+
+		handled := map[string]bool{}
+		for _, stmt := range sw.Body.List {
+			for _, expr := range stmt.(*ast.CaseClause).List {
+				if code, ok := caseCode(expr, consts); ok {
+					handled[code] = true
+				}
+			}
+		}
+		var missing []string
+		for _, code := range codes {
+			if !handled[code] {
+				missing = append(missing, code)
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: switch does not handle %s", pos, strings.Join(missing, ", ")))
+		}
+		return true
+	})
+
+	for _, d := range order {
+		if !d.used {
+			problems = append(problems, fmt.Sprintf("%s: %s directive is not directly followed by a switch statement", d.pos, exhaustiveDirective))
+		}
+	}
+	return problems
+}
+
+// caseCode returns the code matched by the case expression expr, a string
+// literal or a generated ErrCode constant, possibly qualified by its
+// package.
+func caseCode(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			s, err := strconv.Unquote(e.Value)
+			return s, err == nil
+		}
+	case *ast.Ident:
+		code, ok := consts[e.Name]
+		return code, ok
+	case *ast.SelectorExpr:
+		code, ok := consts[e.Sel.Name]
+		return code, ok
+	case *ast.ParenExpr:
+		return caseCode(e.X, consts)
+	}
+	return "", false
+}
+
+// goFiles returns the Go files at paths.
+func goFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		root, recursive := strings.CutSuffix(p, "/...")
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, root)
+			continue
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (!recursive || strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
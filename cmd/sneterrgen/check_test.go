@@ -0,0 +1,92 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestCheckFile(t *testing.T) {
+	c := &Catalog{Package: "billing", Codes: []Code{
+		{Code: "billing.card_declined"},
+		{Code: "billing.expired_card"},
+	}}
+	m, err := newModel(c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	consts := map[string]string{}
+	for _, code := range m.Codes {
+		consts[code.Const] = code.Code.Code
+	}
+	byNamespace := map[string][]string{"billing": {"billing.card_declined", "billing.expired_card"}}
+
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "exhaustive",
+			src: `//sneterr:exhaustive billing
+	switch code {
+	case ErrCodeBillingCardDeclined, "billing.expired_card":
+	}`,
+		},
+		{
+			name: "missing code",
+			src: `//sneterr:exhaustive billing
+	switch code {
+	case ErrCodeBillingCardDeclined:
+	}`,
+			want: []string{"switch does not handle billing.expired_card"},
+		},
+		{
+			name: "unknown namespace",
+			src: `//sneterr:exhaustive shipping
+	switch code {
+	}`,
+			want: []string{`no code in namespace "shipping"`},
+		},
+		{
+			name: "other directive",
+			src: `//sneterr:exhaustiveness billing
+	switch code {
+	}`,
+		},
+		{
+			name: "directive without switch",
+			src: `//sneterr:exhaustive billing
+	_ = code`,
+			want: []string{"directive is not directly followed by a switch statement"},
+		},
+		{
+			name: "directive apart from switch",
+			src: `//sneterr:exhaustive billing
+
+	switch code {
+	}`,
+			want: []string{"directive is not directly followed by a switch statement"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nfunc f(code string) {\n\t" + tt.src + "\n}\n"
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := checkFile(fset, f, consts, byNamespace)
+			if len(got) != len(tt.want) {
+				t.Fatalf("checkFile() = %q, want %d problems", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
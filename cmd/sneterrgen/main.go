@@ -0,0 +1,168 @@
+// Command sneterrgen generates Go code from a catalog of error codes.
+//
+// The catalog is a YAML or JSON file listing the codes of a package:
+//
+//	package: orders
+//	codes:
+//	  - code: OrderNotFound
+//	    message: "order {{.order_id}} was not found"
+//	    category: NotFound
+//	    status: 404
+//	    severity: info
+//	    retryable: false
+//	    access: read
+//	    remediation: "check that the order was not archived"
+//	    links:
+//	      - https://runbooks.example.com/orders/not-found
+//	    fields:
+//	      order_id: string
+//
+// For each code sneterrgen generates an ErrCode constant, a target for
+// errors.Is such as ErrOrderNotFound, the registration of the code in the
+// sneterr catalog and of its message template, and a constructor taking
+// the typed template parameters. It also generates a
+// typed getter for each field, such as OrderID(err) (string, bool).
+//
+// The codes are grouped by namespace, the part of the code before its
+// first dot such as "billing" for "billing.card_declined". For each
+// namespace sneterrgen generates a handler interface with one method per
+// code and a switch helper calling it, such as BillingHandler and
+// SwitchBilling, so handlers implementing the interface stop compiling
+// when a code is added to the namespace.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/servicenetjp/sneterr/cmd/sneterrgen -in errors.yaml -out errors_gen.go
+//
+// With -check, sneterrgen instead checks that the switch statements of
+// the Go files, directories or "dir/..." trees given as arguments which
+// are marked with a directive naming a namespace handle all its codes,
+// and exits with status 1 listing the missing ones otherwise:
+//
+//	//sneterr:exhaustive billing
+//	switch sneterr.CodeOf(err) {
+//	case billing.ErrCodeBillingCardDeclined, billing.ErrCodeBillingExpiredCard:
+//		...
+//	}
+//
+// Cases are matched by string literals or by the generated constants.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func main() {
+	in := flag.String("in", "", "catalog file to read, YAML or JSON")
+	out := flag.String("out", "", "Go file to write, standard output if empty")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated file, overrides the catalog")
+	checkMode := flag.Bool("check", false, "check the switches marked exhaustive in the Go files given as arguments instead of generating code")
+	flag.Parse()
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "sneterrgen: -in is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *checkMode {
+		if err := runCheck(*in, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, "sneterrgen:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := run(*in, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "sneterrgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, pkg string) error {
+	c, err := readCatalog(in)
+	if err != nil {
+		return err
+	}
+	if pkg != "" {
+		c.Package = pkg
+	}
+
+	src, err := generate(c, filepath.Base(in))
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
+
+// runCheck checks the files at paths against the catalog in, printing the
+// problems found.
+func runCheck(in string, paths []string) error {
+	c, err := readCatalog(in)
+	if err != nil {
+		return err
+	}
+	if c.Package == "" {
+		c.Package = "check"
+	}
+	problems, err := check(c, paths)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d exhaustive switch problems", len(problems))
+	}
+	return nil
+}
+
+// readCatalog reads the catalog file in, YAML or JSON.
+func readCatalog(in string) (*Catalog, error) {
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Catalog
+	switch strings.ToLower(filepath.Ext(in)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &c)
+	default:
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", in, err)
+	}
+	return &c, nil
+}
+
+// generate returns the formatted Go source for c.
+func generate(c *Catalog, source string) ([]byte, error) {
+	m, err := newModel(c, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
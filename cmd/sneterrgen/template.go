@@ -0,0 +1,134 @@
+package main
+
+import "text/template"
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by sneterrgen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .UseTime}}
+	"time"
+{{end}}
+	"github.com/servicenetjp/sneterr"
+)
+
+// Error codes of the package.
+const (
+{{- range .Codes}}
+	{{.Const}} = {{printf "%q" .Code.Code}}
+{{- end}}
+)
+
+// Targets of the error codes, for errors.Is.
+var (
+{{- range .Codes}}
+	{{.Target}} = sneterr.Target({{.Const}})
+{{- end}}
+)
+
+func init() {
+{{- range .Codes}}
+	sneterr.Register(sneterr.CodeInfo{
+		Code: {{.Const}},
+		{{- if .Category}}
+		Category: {{printf "%q" .Category}},
+		{{- end}}
+		{{- if .Severity}}
+		Severity: sneterr.{{.Severity}},
+		{{- end}}
+		{{- if .Params}}
+		Fields: sneterr.Schema{
+			{{- range .Params}}
+			{{printf "%q" .Key}}: sneterr.{{.FieldType}},
+			{{- end}}
+		},
+		{{- end}}
+		{{- if .Retryable}}
+		Retryable: true,
+		{{- end}}
+		{{- if .Access}}
+		Access: sneterr.{{.Access}},
+		{{- end}}
+		{{- if .Status}}
+		Status: {{.Status}},
+		{{- end}}
+		{{- if .Remediation}}
+		Remediation: {{printf "%q" .Remediation}},
+		{{- end}}
+		{{- if .Links}}
+		Links: []string{
+			{{- range .Links}}
+			{{printf "%q" .}},
+			{{- end}}
+		},
+		{{- end}}
+	})
+	{{- if .Message}}
+	sneterr.Template({{.Const}}, {{printf "%q" .Message}})
+	{{- end}}
+{{- end}}
+}
+{{range .Codes}}
+{{- if .Message}}
+// {{.Name}}Params are the message parameters of errors with code
+// {{.Const}}.
+type {{.Name}}Params struct {
+{{- range .Params}}
+	{{.Name}} {{.Type}} ` + "`" + `sneterr:"{{.Key}}"` + "`" + `
+{{- end}}
+}
+
+// New{{.Name}} returns an error with code {{.Const}}
+// and a message rendered from params.
+func New{{.Name}}(params {{.Name}}Params, opts ...sneterr.Option) sneterr.Error {
+	sneterr.Helper()
+	return sneterr.NewT({{.Const}}, params, opts...)
+}
+{{else}}
+// New{{.Name}} returns an error with code {{.Const}}.
+func New{{.Name}}(message string, opts ...sneterr.Option) sneterr.Error {
+	sneterr.Helper()
+	return sneterr.New({{.Const}}, message, opts...)
+}
+{{end}}
+{{- end}}
+{{- range .Namespaces}}
+// {{.Handler}} handles every code of the
+{{- if .Namespace}} {{.Namespace}} namespace{{else}} package without namespace{{end}}, for
+// {{.Switch}}. Each code has its own method, so an implementation stops
+// compiling when a code is added to the catalog instead of silently
+// falling through.
+type {{.Handler}}[T any] interface {
+{{- range .Codes}}
+	{{.Method}}(err sneterr.Error) T
+{{- end}}
+
+	// Default handles errors without any of the codes.
+	Default(err error) T
+}
+
+// {{.Switch}} calls the method of h handling the code of the first
+// sneterr.Error in the chain of err, or h.Default if it has none of the
+// codes of {{.Handler}}.
+func {{.Switch}}[T any](err error, h {{.Handler}}[T]) T {
+	e, ok := sneterr.As[sneterr.Error](err)
+	if !ok {
+		return h.Default(err)
+	}
+	switch e.Code() {
+{{- range .Codes}}
+	case {{.Const}}:
+		return h.{{.Method}}(e)
+{{- end}}
+	}
+	return h.Default(err)
+}
+{{end}}
+{{- range .Getters}}
+// {{.Name}} returns the {{printf "%q" .Key}} field of err.
+func {{.Name}}(err error) ({{.Type}}, bool) {
+	return sneterr.Field[{{.Type}}](err, {{printf "%q" .Key}})
+}
+{{end -}}
+`))
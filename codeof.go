@@ -0,0 +1,22 @@
+package sneterr
+
+// CodeOf returns the code of the first Error in the chain of err, or
+// ErrCodeUnknown if there is none. A nil error has an empty code.
+//
+// CodeOf does not allocate nor render messages, so it can be used to
+// classify errors on hot paths. Chains of errors with custom Unwrap
+// methods are only guaranteed not to allocate if those methods do not.
+func CodeOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	code := ErrCodeUnknown
+	walk(err, func(e error) bool {
+		if se, ok := e.(Error); ok {
+			code = se.Code()
+			return false
+		}
+		return true
+	})
+	return code
+}
@@ -0,0 +1,168 @@
+package sneterr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Codes of configuration errors.
+const (
+	// Code of the errors returned by NewConfigError, for one setting.
+	ErrCodeConfigInvalid = "ConfigInvalid"
+
+	// Code of the MultiError returned by ConfigErrors.Err.
+	ErrCodeConfig = "ConfigErrors"
+)
+
+// Field keys of the errors returned by NewConfigError.
+const (
+	FieldConfigKey      = "config_key"
+	FieldConfigExpected = "config_expected"
+	FieldConfigValue    = "config_value"
+	FieldConfigSource   = "config_source"
+)
+
+// RedactedValue replaces the values of secret settings in configuration
+// errors.
+const RedactedValue = "[redacted]"
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodeConfigInvalid,
+		Category: CategoryInvalidArgument,
+		Severity: SeverityCritical,
+		Fields: Schema{
+			FieldConfigKey:      FieldString,
+			FieldConfigExpected: FieldString,
+			FieldConfigValue:    FieldAny,
+			FieldConfigSource:   FieldString,
+		},
+	})
+	Register(CodeInfo{
+		Code:     ErrCodeConfig,
+		Category: CategoryInvalidArgument,
+		Severity: SeverityCritical,
+	})
+}
+
+// A ConfigError describes an invalid configuration setting.
+type ConfigError struct {
+	// Path of the setting, such as "database.pool.max_size".
+	Key string
+
+	// Type or constraint the value must satisfy, such as "int between 1
+	// and 100" or "set".
+	Expected string
+
+	// Value provided, nil if the setting is missing.
+	Value interface{}
+
+	// Whether Value is a secret, such as a password, replaced with
+	// RedactedValue in the error.
+	Secret bool
+
+	// Where Value comes from, such as "config.yaml:12" or "env
+	// DATABASE_URL".
+	Source string
+}
+
+// NewConfigError returns an Error with ErrCodeConfigInvalid describing c,
+// with its key, expectation, value and source as fields. The value of a
+// secret setting is replaced with RedactedValue.
+func NewConfigError(c ConfigError, opts ...Option) Error {
+	value := redactField(c.Value)
+	if c.Secret && value != nil {
+		value = RedactedValue
+	}
+
+	msg := "config " + c.Key
+	if c.Source != "" {
+		msg += " from " + c.Source
+	}
+	if value == nil {
+		msg += " is missing"
+	} else {
+		msg += " is " + configValueString(value)
+	}
+	if c.Expected != "" {
+		msg += ", expected " + c.Expected
+	}
+
+	b := newBaseError(ErrCodeConfigInvalid, msg, nil, "", 0)
+	b.fields = Fields{FieldConfigKey: c.Key}
+	if c.Expected != "" {
+		b.fields[FieldConfigExpected] = c.Expected
+	}
+	if value != nil {
+		b.fields[FieldConfigValue] = value
+	}
+	if c.Source != "" {
+		b.fields[FieldConfigSource] = c.Source
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// configValueString returns v as written in messages.
+func configValueString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		if s == RedactedValue {
+			return s
+		}
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+// ConfigErrors collects the configuration errors found at startup, so
+// operators see all the misconfigurations at once instead of fixing them
+// one restart at a time:
+//
+//	var errs sneterr.ConfigErrors
+//	if cfg.Pool.MaxSize < 1 {
+//		errs.Add(sneterr.ConfigError{Key: "database.pool.max_size",
+//			Expected: "int >= 1", Value: cfg.Pool.MaxSize, Source: path})
+//	}
+//	if cfg.Password == "" {
+//		errs.Add(sneterr.ConfigError{Key: "database.password",
+//			Expected: "set", Secret: true, Source: "env DB_PASSWORD"})
+//	}
+//	if err := errs.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The zero ConfigErrors is ready to use.
+type ConfigErrors struct {
+	errs []error
+}
+
+// Add records the invalid setting c.
+func (c *ConfigErrors) Add(e ConfigError) {
+	Helper()
+	c.errs = append(c.errs, NewConfigError(e))
+}
+
+// AddErr records err, such as a file which could not be parsed.
+func (c *ConfigErrors) AddErr(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Len returns the number of errors recorded.
+func (c *ConfigErrors) Len() int {
+	return len(c.errs)
+}
+
+// Err returns a MultiError with ErrCodeConfig grouping the recorded
+// errors, or nil if there are none.
+func (c *ConfigErrors) Err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	msg := "1 configuration error"
+	if len(c.errs) > 1 {
+		msg = strconv.Itoa(len(c.errs)) + " configuration errors"
+	}
+	return NewMultiError(ErrCodeConfig, msg, append([]error(nil), c.errs...))
+}
@@ -0,0 +1,184 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Field keys of the errors returned by NewConflict and
+// NewPreconditionFailed.
+const (
+	FieldExpected = "expected"
+	FieldActual   = "actual"
+	FieldDiff     = "diff"
+
+	// Opaque token of the current version of the resource, set by
+	// WithRetryToken.
+	FieldRetryToken = "retry_token"
+)
+
+// A PatchOp is a JSON Patch (RFC 6902) operation.
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// NewConflict returns an Error with code reporting that a resource changed
+// since the caller read it, rendered with http.StatusConflict.
+//
+// expected is the version or document the caller based its request on and
+// actual the current one. Versions, such as numbers or ETags, are attached
+// as the FieldExpected and FieldActual fields. Documents, which encode to
+// JSON objects or arrays, are attached as the JSON Patch turning expected
+// into actual in the FieldDiff field, so the client can merge its changes
+// instead of blindly retrying.
+//
+// Passing WithRetryToken in opts lets the client retry without fetching
+// the resource again.
+func NewConflict(code, message string, expected, actual interface{}, opts ...Option) Error {
+	b := newBaseError(code, message, nil, "", 0)
+	b.status = http.StatusConflict
+	b.fields = conflictFields(expected, actual)
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// NewPreconditionFailed is like NewConflict but for failed conditional
+// requests, rendered with http.StatusPreconditionFailed.
+func NewPreconditionFailed(code, message string, expected, actual interface{}, opts ...Option) Error {
+	b := newBaseError(code, message, nil, "", 0)
+	b.status = http.StatusPreconditionFailed
+	b.fields = conflictFields(expected, actual)
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// WithRetryToken attaches token, an opaque token of the current version of
+// the resource such as its ETag, to a conflict error. The client merges
+// its changes with the current resource and echoes the token back with
+// its retry, so the server can check nothing changed in between: a
+// fetch-merge-retry flow without a separate GET. sneterrhttp exposes the
+// token in the ETag header of the response.
+func WithRetryToken(token string) Option {
+	return WithField(FieldRetryToken, token)
+}
+
+// RetryToken returns the retry token attached to err by WithRetryToken,
+// including when err was decoded from a response by FromResponse.
+func RetryToken(err error) (string, bool) {
+	token, ok := FieldsOf(err)[FieldRetryToken].(string)
+	return token, ok && token != ""
+}
+
+// ConflictDiff returns the JSON Patch attached to err by NewConflict or
+// NewPreconditionFailed, including when err was decoded from a response
+// by FromResponse.
+func ConflictDiff(err error) ([]PatchOp, bool) {
+	switch v := FieldsOf(err)[FieldDiff].(type) {
+	case nil:
+		return nil, false
+	case []PatchOp:
+		return v, true
+	default:
+		b, mErr := json.Marshal(v)
+		if mErr != nil {
+			return nil, false
+		}
+		var ops []PatchOp
+		if json.Unmarshal(b, &ops) != nil {
+			return nil, false
+		}
+		return ops, true
+	}
+}
+
+// conflictFields returns the fields describing expected and actual, which
+// are redacted like with WithField.
+func conflictFields(expected, actual interface{}) Fields {
+	expected, actual = redactField(expected), redactField(actual)
+	a, aErr := toJSONValue(expected)
+	b, bErr := toJSONValue(actual)
+	if aErr == nil && bErr == nil && isDocument(a) && isDocument(b) {
+		return Fields{FieldDiff: diffValues(nil, "", a, b)}
+	}
+	return Fields{FieldExpected: expected, FieldActual: actual}
+}
+
+// Diff returns the JSON Patch operations turning from into to, compared
+// through their JSON encoding. Objects are compared member by member;
+// arrays and other values which differ are replaced as a whole. Values
+// which cannot be encoded are replaced as a whole.
+func Diff(from, to interface{}) []PatchOp {
+	a, aErr := toJSONValue(from)
+	b, bErr := toJSONValue(to)
+	if aErr != nil || bErr != nil {
+		return []PatchOp{{Op: "replace", Path: "", Value: to}}
+	}
+	return diffValues(nil, "", a, b)
+}
+
+func diffValues(ops []PatchOp, path string, a, b interface{}) []PatchOp {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if !aok || !bok {
+		if !reflect.DeepEqual(a, b) {
+			ops = append(ops, PatchOp{Op: "replace", Path: path, Value: b})
+		}
+		return ops
+	}
+
+	keys := make([]string, 0, len(am)+len(bm))
+	for k := range am {
+		keys = append(keys, k)
+	}
+	for k := range bm {
+		if _, ok := am[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		av, inA := am[k]
+		bv, inB := bm[k]
+		switch {
+		case !inB:
+			ops = append(ops, PatchOp{Op: "remove", Path: p})
+		case !inA:
+			ops = append(ops, PatchOp{Op: "add", Path: p, Value: bv})
+		default:
+			ops = diffValues(ops, p, av, bv)
+		}
+	}
+	return ops
+}
+
+// escapePointer escapes a JSON Pointer (RFC 6901) reference token.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// toJSONValue returns v as decoded from its JSON encoding.
+func toJSONValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+// isDocument reports whether v is a decoded JSON object or array.
+func isDocument(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
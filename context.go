@@ -0,0 +1,187 @@
+package sneterr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Codes of the errors returned by FromContextErr.
+const (
+	ErrCodeTimeout  = string(CategoryTimeout)
+	ErrCodeCanceled = string(CategoryCanceled)
+)
+
+// Field keys of the errors returned by FromContextErr.
+const (
+	// The deadline of the context.
+	FieldDeadline = "deadline"
+
+	// The party which timed out or canceled the operation, one of
+	// PartyLocal, PartyCaller and PartyDownstream.
+	FieldTimeoutParty = "timeout_party"
+)
+
+// Parties of the FieldTimeoutParty field, for assigning latency blame
+// across services.
+const (
+	// The deadline of the context of the operation expired: the local
+	// service ran out of time, whoever set the deadline.
+	PartyLocal = "local"
+
+	// The context of the operation was canceled, typically by the caller
+	// of the local service giving up on it.
+	PartyCaller = "caller"
+
+	// The downstream service called by the operation timed out or
+	// canceled the call while its context was still live.
+	PartyDownstream = "downstream"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:      ErrCodeTimeout,
+		Category:  CategoryTimeout,
+		Severity:  SeverityWarn,
+		Fields:    Schema{FieldDeadline: FieldTime, FieldTimeoutParty: FieldString},
+		Retryable: true,
+	})
+	Register(CodeInfo{
+		Code:     ErrCodeCanceled,
+		Category: CategoryCanceled,
+		Severity: SeverityInfo,
+		Fields:   Schema{FieldDeadline: FieldTime, FieldTimeoutParty: FieldString},
+	})
+}
+
+// FromContextErr classifies err, returned by an operation running with
+// ctx, as a timeout or a cancellation. The returned Error wraps err with
+// ErrCodeTimeout when err is context.DeadlineExceeded or a net.Error
+// timeout somewhere in its chain, and ErrCodeCanceled when it is
+// context.Canceled. Errors returned because ctx is done, such as those of
+// drivers which do not wrap the context error, are classified by ctx.Err.
+// The deadline of ctx, if it has one, is recorded in the FieldDeadline
+// field.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the Error interface. If err is nil FromContextErr
+// returns nil.
+func FromContextErr(ctx context.Context, err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	code := contextCode(ctx, err)
+	if code == "" {
+		if e, ok := err.(Error); ok {
+			return e
+		}
+		return newError(2, ErrCodeUnknown, err.Error(), err)
+	}
+
+	party := timeoutParty(ctx)
+	var e Error
+	if errors.As(err, &e) && e.Code() == code {
+		if party == "" || attributed(e) {
+			return e
+		}
+		return With(e, WithField(FieldTimeoutParty, party))
+	}
+
+	msg := "operation timed out"
+	if code == ErrCodeCanceled {
+		msg = "operation canceled"
+	}
+	b := newBaseError(code, msg, err, "", 0)
+	if party != "" {
+		b.fields = Fields{FieldTimeoutParty: party}
+		if d, ok := ctx.Deadline(); ok {
+			b.fields[FieldDeadline] = d
+		}
+	}
+	return finishError(2, b)
+}
+
+// TimeoutParty returns the party which timed out or canceled the
+// operation which failed with err, as recorded by FromContextErr.
+func TimeoutParty(err error) (string, bool) {
+	return Field[string](err, FieldTimeoutParty)
+}
+
+// timeoutParty returns the party blamed for a timeout or cancellation of
+// an operation running with ctx, or an empty string if ctx is nil or was
+// done for another reason.
+func timeoutParty(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	switch ctx.Err() {
+	case nil:
+		return PartyDownstream
+	case context.DeadlineExceeded:
+		return PartyLocal
+	case context.Canceled:
+		return PartyCaller
+	}
+	return ""
+}
+
+// attributed reports whether e was given a party by the local process.
+func attributed(e Error) bool {
+	if Frozen(e) {
+		return false
+	}
+	f, ok := e.(interface{ Fields() Fields })
+	if !ok {
+		return false
+	}
+	_, set := f.Fields()[FieldTimeoutParty]
+	return set
+}
+
+// contextCode returns the code FromContextErr gives to err, or an empty
+// string if err is neither a timeout nor a cancellation.
+func contextCode(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrCodeCanceled
+	case isTimeout(err):
+		return ErrCodeTimeout
+	case errors.Is(err, Target(ErrCodeTimeout)):
+		return ErrCodeTimeout
+	case errors.Is(err, Target(ErrCodeCanceled)):
+		return ErrCodeCanceled
+	}
+	if rf, ok := AsRequestFailure(err); ok && rf.StatusCode() == http.StatusGatewayTimeout {
+		return ErrCodeTimeout
+	}
+
+	if ctx == nil {
+		return ""
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return ErrCodeTimeout
+	case context.Canceled:
+		return ErrCodeCanceled
+	}
+	return ""
+}
+
+// isTimeout reports whether the chain of err has a net.Error timeout. It
+// walks the chain itself rather than using errors.As, which allocates, as
+// it runs for CategoryOf.
+func isTimeout(err error) bool {
+	timeout := false
+	walk(err, func(e error) bool {
+		if ne, ok := e.(net.Error); ok && ne.Timeout() {
+			timeout = true
+			return false
+		}
+		return true
+	})
+	return timeout
+}
@@ -0,0 +1,116 @@
+package sneterr
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// FieldDebug is the field key marking errors of requests being debugged,
+// set by WithDebug and Escalate.
+const FieldDebug = "debug"
+
+// DebugBaggageKey is the key of the OpenTelemetry baggage member flagging
+// a request for debugging, see DebugFromBaggage.
+const DebugBaggageKey = "sneterr.debug"
+
+type debugKey struct{}
+
+// ContextWithDebug returns a copy of ctx flagging the request it belongs
+// to for debugging. The flag is not applied to errors by itself: the error
+// the request fails with is escalated by the transport with Escalate, and
+// errors created with the WithDebug option of ctx are captured in full.
+func ContextWithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+// DebugFromContext reports whether ctx belongs to a request flagged for
+// debugging by ContextWithDebug.
+func DebugFromContext(ctx context.Context) bool {
+	debug, _ := ctx.Value(debugKey{}).(bool)
+	return debug
+}
+
+// DebugFromBaggage reports whether baggage, the value of a W3C baggage
+// header as propagated by OpenTelemetry, has a DebugBaggageKey member set
+// to "1" or "true".
+func DebugFromBaggage(baggage string) bool {
+	for _, member := range strings.Split(baggage, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		if ok && strings.TrimSpace(key) == DebugBaggageKey {
+			return debugValue(strings.TrimSpace(value))
+		}
+	}
+	return false
+}
+
+// debugValue reports whether v turns debugging on.
+func debugValue(v string) bool {
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// WithDebug marks the error with the FieldDebug field when ctx belongs to
+// a request flagged for debugging, so it is captured in full: its whole
+// stack is recorded instead of its top frames, and SampledHook and
+// RateLimitedHook never drop it. It does nothing otherwise.
+//
+//	return sneterr.Wrap(err, "PriceUnavailable", "no price for item",
+//		sneterr.WithDebug(ctx))
+func WithDebug(ctx context.Context) Option {
+	if !DebugFromContext(ctx) {
+		return nil
+	}
+	return WithField(FieldDebug, true)
+}
+
+// Debug reports whether err belongs to a request flagged for debugging, as
+// marked by WithDebug or Escalate.
+func Debug(err error) bool {
+	debug, _ := Field[bool](err, FieldDebug)
+	return debug
+}
+
+var (
+	debugHooksMu sync.RWMutex
+	debugHooks   []Hook
+)
+
+// RegisterDebugHook adds h to the hooks invoked by Escalate with the errors
+// of requests flagged for debugging, such as a journal keeping them with
+// a long retention. Unlike the hooks of RegisterHook they are not meant to
+// be sampled.
+func RegisterDebugHook(h Hook) {
+	if h == nil {
+		return
+	}
+	debugHooksMu.Lock()
+	debugHooks = append(debugHooks, h)
+	debugHooksMu.Unlock()
+}
+
+// Escalate is called by transports, such as the sneterrhttp middleware,
+// with the error a request failed with. If ctx belongs to a request
+// flagged for debugging it returns a copy of err marked with the
+// FieldDebug field, after calling the hooks registered by
+// RegisterDebugHook with it. It returns err unchanged otherwise.
+//
+// The stack of err was recorded when it was created, so it is only whole
+// if err was created with WithDebug.
+func Escalate(ctx context.Context, err error) error {
+	if err == nil || !DebugFromContext(ctx) {
+		return err
+	}
+	e, ok := err.(Error)
+	if !ok || !Debug(e) {
+		e = WithFields(err, Fields{FieldDebug: true})
+	}
+
+	debugHooksMu.RLock()
+	hs := debugHooks
+	debugHooksMu.RUnlock()
+	for _, h := range hs {
+		h(e)
+	}
+	return e
+}
@@ -0,0 +1,125 @@
+package sneterr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// ErrCodeDecode is the code of the errors returned by Decoder.Next for
+// lines which are not a valid error event.
+const ErrCodeDecode = "DecodeError"
+
+// FieldLine is the field key holding the line number of an invalid event.
+const FieldLine = "line"
+
+// A Decoder reads a stream of newline delimited JSON error events, as
+// produced by encoding errors with their MarshalJSON method one per line,
+// and reconstructs the errors they describe.
+//
+// Reading happens in a background goroutine so Next can return as soon as
+// its context is done. Close must be called when the Decoder is no longer
+// needed.
+type Decoder struct {
+	r *bufio.Reader
+
+	start sync.Once
+	close sync.Once
+	lines chan decodedLine
+	done  chan struct{}
+
+	line int
+	err  error
+}
+
+type decodedLine struct {
+	data []byte
+	err  error
+}
+
+// NewDecoder returns a Decoder reading events from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:     bufio.NewReader(r),
+		lines: make(chan decodedLine),
+		done:  make(chan struct{}),
+	}
+}
+
+// Next returns the next error of the stream.
+//
+// It returns io.EOF at the end of the stream, the context error if ctx is
+// done first, and the read error if reading failed. A line which is not a
+// valid event yields an error with ErrCodeDecode and the line number in its
+// FieldLine field; decoding can continue past it.
+func (d *Decoder) Next(ctx context.Context) (Error, error) {
+	d.start.Do(func() { go d.read() })
+
+	for {
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		var l decodedLine
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case l = <-d.lines:
+		}
+		if l.err != nil {
+			d.err = l.err
+			continue
+		}
+
+		d.line++
+		if len(bytes.TrimSpace(l.data)) == 0 {
+			continue
+		}
+		var j jsonError
+		if err := json.Unmarshal(l.data, &j); err != nil {
+			return nil, d.decodeError("invalid error event", err)
+		}
+		if j.Code == "" {
+			return nil, d.decodeError("error event has no code", nil)
+		}
+		return fromJSON(&j).(Error), nil
+	}
+}
+
+// Close stops the background reader. It does not close the underlying
+// reader, which must be closed to interrupt a blocked read.
+func (d *Decoder) Close() error {
+	d.close.Do(func() { close(d.done) })
+	return nil
+}
+
+func (d *Decoder) decodeError(message string, err error) Error {
+	b := newBaseError(ErrCodeDecode, message, err, "", 0)
+	b.fields = Fields{FieldLine: d.line}
+	return b
+}
+
+// read sends each line of the stream to d.lines until it fails or the
+// Decoder is closed.
+func (d *Decoder) read() {
+	for {
+		data, err := d.r.ReadBytes('\n')
+		if len(data) > 0 {
+			select {
+			case d.lines <- decodedLine{data: data}:
+			case <-d.done:
+				return
+			}
+		}
+		if err != nil {
+			select {
+			case d.lines <- decodedLine{err: err}:
+			case <-d.done:
+			}
+			return
+		}
+	}
+}
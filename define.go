@@ -0,0 +1,72 @@
+package sneterr
+
+import "sync/atomic"
+
+// Define returns an Error with code and message, configured by opts, to be
+// declared once and returned as is, like the sentinel errors of errors.New:
+//
+//	var ErrRejected = sneterr.Define("Rejected", "request rejected by policy",
+//		sneterr.WithStatus(http.StatusForbidden))
+//
+// As errors are immutable, the error caches its Error string and JSON
+// encoding when first rendered, so errors returned on hot paths are not
+// rendered again each time. Copies made by With and the helpers built on
+// it are not cached. The cause set by WithCause, if any, must be immutable
+// too.
+//
+// The location of the call to Define is recorded, but not a creation time,
+// and hooks are not notified: the error is defined rather than occurring.
+func Define(code, message string, opts ...Option) Error {
+	b := newBaseError(code, message, nil, "", 0)
+	b.apply(opts)
+	b.stack = callers(1)
+	b.file, b.line = location(b.stack)
+	b.cache = new(textCache)
+	return b
+}
+
+// textCache holds the renderings of an error returned by Define.
+type textCache struct {
+	text atomic.Pointer[string]
+	json atomic.Pointer[cachedJSON]
+}
+
+// cachedJSON is the JSON encoding of an error, with the fingerprint
+// setting it was encoded with.
+type cachedJSON struct {
+	fingerprint bool
+	data        []byte
+}
+
+// error returns the Error string of b, rendered on first use. Concurrent
+// first uses may render it more than once, with the same result.
+func (c *textCache) error(b baseError) string {
+	if t := c.text.Load(); t != nil {
+		return *t
+	}
+	t := b.render()
+	c.text.Store(&t)
+	return t
+}
+
+// marshal returns the JSON encoding of b, encoded again only when the
+// setting of SetJSONFingerprint changed since it was cached. The encoding
+// is not cached while SetJSONDedupKey is set, as the dedup key of an error
+// without creation time depends on the current time. The encoding is
+// copied, as callers may modify it.
+func (c *textCache) marshal(b baseError) ([]byte, error) {
+	if jsonDedupWindow.Load() > 0 {
+		return b.marshal()
+	}
+	fingerprint := jsonFingerprint.Load()
+	j := c.json.Load()
+	if j == nil || j.fingerprint != fingerprint {
+		data, err := b.marshal()
+		if err != nil {
+			return nil, err
+		}
+		j = &cachedJSON{fingerprint: fingerprint, data: data}
+		c.json.Store(j)
+	}
+	return append([]byte(nil), j.data...), nil
+}
@@ -0,0 +1,52 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDefineCache(t *testing.T) {
+	err := Define("Rejected", "request rejected", WithField("policy", "geo"))
+	if got, want := err.Error(), err.(*baseError).render(); got != want || err.Error() != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	first, _ := json.Marshal(err)
+	second, _ := json.Marshal(err)
+	if string(first) != string(second) {
+		t.Errorf("encodings differ: %s and %s", first, second)
+	}
+
+	copied := With(err, WithField("policy", "ip"))
+	if copied.(*baseError).cache != nil {
+		t.Errorf("copy made by With shares the cache")
+	}
+	if data, _ := json.Marshal(copied); string(data) == string(first) {
+		t.Errorf("copy encoded as the defined error: %s", data)
+	}
+}
+
+func TestDefineCacheDedupKey(t *testing.T) {
+	const window = 10 * time.Millisecond
+	SetJSONDedupKey(window)
+	defer SetJSONDedupKey(0)
+
+	err := Define("Rejected", "request rejected")
+	key := func() string {
+		var j struct {
+			DedupKey string `json:"dedupKey"`
+		}
+		data, _ := json.Marshal(err)
+		if uErr := json.Unmarshal(data, &j); uErr != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", data, uErr)
+		}
+		return j.DedupKey
+	}
+
+	before := key()
+	time.Sleep(2 * window)
+	if after := key(); after == before {
+		t.Errorf("dedupKey = %q after %v, want a new window", after, 2*window)
+	}
+}
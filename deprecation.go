@@ -0,0 +1,84 @@
+package sneterr
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrCodeDeprecated is the code of the warnings returned by
+// DeprecationWarning.
+const ErrCodeDeprecated = "Deprecated"
+
+// Field keys of the warnings returned by DeprecationWarning.
+const (
+	FieldDeprecatedCode = "deprecated_code"
+	FieldSunset         = "sunset"
+)
+
+// DeprecationWarning returns a Warning announcing that the feature
+// identified by code is deprecated and stops being served at sunset. A zero
+// sunset means no date was set.
+func DeprecationWarning(code string, sunset time.Time) Warning {
+	msg := code + " is deprecated"
+	fields := Fields{FieldDeprecatedCode: code}
+	if !sunset.IsZero() {
+		msg += " and will be removed on " + sunset.UTC().Format(time.DateOnly)
+		fields[FieldSunset] = sunset.UTC()
+	}
+	return NewWarning(ErrCodeDeprecated, msg, fields)
+}
+
+// WarnIfDeprecated adds a DeprecationWarning to the Warnings collector of
+// ctx when code is registered as deprecated in the catalog. It reports
+// whether code is deprecated.
+func WarnIfDeprecated(ctx context.Context, code string) bool {
+	info, ok := Lookup(code)
+	if !ok || !info.Deprecated() {
+		return false
+	}
+	AddWarning(ctx, DeprecationWarning(code, info.Sunset))
+	return true
+}
+
+// SetDeprecationHeaders sets the Deprecation and Sunset headers of h from
+// the deprecation warnings in ws. When several are present the earliest
+// sunset is used. Headers are left untouched if ws has no deprecation
+// warning.
+func SetDeprecationHeaders(h http.Header, ws []Warning) {
+	var (
+		deprecated    bool
+		since, sunset time.Time
+	)
+	for _, w := range ws {
+		if w.Code != ErrCodeDeprecated {
+			continue
+		}
+		deprecated = true
+
+		code, _ := w.Fields[FieldDeprecatedCode].(string)
+		if info, ok := Lookup(code); ok && !info.DeprecatedSince.IsZero() {
+			if since.IsZero() || info.DeprecatedSince.Before(since) {
+				since = info.DeprecatedSince
+			}
+		}
+		if t, ok := w.Fields[FieldSunset].(time.Time); ok {
+			if sunset.IsZero() || t.Before(sunset) {
+				sunset = t
+			}
+		}
+	}
+	if !deprecated {
+		return
+	}
+
+	if since.IsZero() {
+		h.Set("Deprecation", "true")
+	} else {
+		h.Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+	}
+	if !sunset.IsZero() {
+		h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,91 @@
+package sneterr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+)
+
+// An EnforceAction reacts to an error without a registered code reaching a
+// transport boundary, see EnforceRegisteredCodes.
+type EnforceAction func(err error)
+
+var enforceAction atomic.Pointer[EnforceAction]
+
+// EnforceRegisteredCodes makes CheckBoundary call action for the errors
+// reaching a transport boundary without a code registered in the catalog,
+// including foreign errors and errors with ErrCodeUnknown. It is meant for
+// staging environments, to drive the coverage of the taxonomy to every
+// error before features ship:
+//
+//	if env == "staging" {
+//		sneterr.EnforceRegisteredCodes(sneterr.FatalAction(logger))
+//	}
+//
+// PanicAction and FatalAction are loud actions; a function paging the
+// team is another. A nil action, the default, disables the enforcement.
+func EnforceRegisteredCodes(action EnforceAction) {
+	if action == nil {
+		enforceAction.Store(nil)
+		return
+	}
+	enforceAction.Store(&action)
+}
+
+// PanicAction is an EnforceAction panicking with the error.
+func PanicAction(err error) {
+	panic(fmt.Sprintf("sneterr: error without registered code reached a transport boundary: %v", err))
+}
+
+// FatalAction returns an EnforceAction logging the error with logger, or
+// slog.Default() when nil, then exiting the process with status 1.
+func FatalAction(logger *slog.Logger) EnforceAction {
+	return func(err error) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		l.LogAttrs(context.Background(), SeverityCritical.Level(), "error without registered code reached a transport boundary",
+			slog.String("code", CodeOf(err)),
+			slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// CheckBoundary is called by transports, such as the sneterrhttp
+// middleware, with the errors they are about to send. It calls the action
+// set by EnforceRegisteredCodes if err has no code registered in the
+// catalog. The errors grouped by a MultiError are checked instead of its
+// own code. CheckBoundary does nothing when enforcement is disabled or err
+// is nil.
+func CheckBoundary(err error) {
+	action := enforceAction.Load()
+	if action == nil || err == nil {
+		return
+	}
+	if bad := unregistered(err); bad != nil {
+		(*action)(bad)
+	}
+}
+
+// unregistered returns the first error without a registered code among err
+// and the errors it groups, or nil if there is none.
+func unregistered(err error) error {
+	if m, ok := err.(MultiError); ok {
+		if _, ok := Lookup(m.Code()); !ok {
+			for _, e := range m.Errors() {
+				if bad := unregistered(e); bad != nil {
+					return bad
+				}
+			}
+			return nil
+		}
+	}
+	code := CodeOf(err)
+	if _, ok := Lookup(code); !ok || code == ErrCodeUnknown {
+		return err
+	}
+	return nil
+}
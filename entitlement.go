@@ -0,0 +1,92 @@
+package sneterr
+
+import "net/http"
+
+// ErrCodeEntitlementRequired is the code of the errors returned by
+// NewEntitlementError.
+const ErrCodeEntitlementRequired = "EntitlementRequired"
+
+// Field keys of the errors returned by NewEntitlementError.
+const (
+	FieldEntitlement = "entitlement"
+	FieldPlan        = "plan"
+	FieldUpgradeURL  = "upgrade_url"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodeEntitlementRequired,
+		Category: CategoryPermissionDenied,
+		Severity: SeverityInfo,
+		Status:   http.StatusPaymentRequired,
+		Fields: Schema{
+			FieldEntitlement: FieldString,
+			FieldPlan:        FieldString,
+			FieldUpgradeURL:  FieldString,
+		},
+	})
+}
+
+// An Entitlement describes a feature the caller's license or plan does
+// not include.
+type Entitlement struct {
+	// The missing entitlement, such as "reports.export".
+	Name string
+
+	// The plan including it, such as "business", if any.
+	Plan string
+
+	// Where the caller can upgrade, if known.
+	UpgradeURL string
+}
+
+// NewEntitlementError returns an Error with ErrCodeEntitlementRequired
+// reporting that the caller is not entitled to e, rendered with
+// http.StatusPaymentRequired. Unlike a permission error, which no plan
+// lifts, the missing entitlement, the plan including it and the upgrade
+// URL are attached as fields, so clients can offer the upgrade:
+//
+//	if !account.Has("reports.export") {
+//		return sneterr.NewEntitlementError(sneterr.Entitlement{
+//			Name:       "reports.export",
+//			Plan:       "business",
+//			UpgradeURL: "https://example.com/billing/upgrade?plan=business",
+//		})
+//	}
+func NewEntitlementError(e Entitlement, opts ...Option) Error {
+	msg := "your plan does not include " + e.Name
+	if e.Plan != "" {
+		msg += ", upgrade to " + e.Plan + " to use it"
+	}
+
+	b := newBaseError(ErrCodeEntitlementRequired, msg, nil, "", 0)
+	b.fields = Fields{FieldEntitlement: e.Name}
+	if e.Plan != "" {
+		b.fields[FieldPlan] = e.Plan
+	}
+	if e.UpgradeURL != "" {
+		b.fields[FieldUpgradeURL] = e.UpgradeURL
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// EntitlementOf returns the entitlement reported by the first error with
+// ErrCodeEntitlementRequired in the chain of err.
+func EntitlementOf(err error) (Entitlement, bool) {
+	var e Entitlement
+	found := false
+	walk(err, func(err error) bool {
+		se, ok := err.(Error)
+		if !ok || se.Code() != ErrCodeEntitlementRequired {
+			return true
+		}
+		found = true
+		fields := matchedFields(se)
+		e.Name, _ = fields[FieldEntitlement].(string)
+		e.Plan, _ = fields[FieldPlan].(string)
+		e.UpgradeURL, _ = fields[FieldUpgradeURL].(string)
+		return false
+	})
+	return e, found
+}
@@ -0,0 +1,55 @@
+//go:build !plan9
+
+package sneterr
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// errnoCategories classifies the system error numbers of the platform.
+var errnoCategories = map[syscall.Errno]Category{}
+
+// Errno returns the first system error number in the chain of err.
+func Errno(err error) (syscall.Errno, bool) {
+	var (
+		errno syscall.Errno
+		found bool
+	)
+	walk(err, func(e error) bool {
+		errno, found = e.(syscall.Errno)
+		return !found
+	})
+	return errno, found
+}
+
+// WindowsErrorCode returns the first Windows system error code in the
+// chain of err, such as 5 for ERROR_ACCESS_DENIED or 10061 for
+// WSAECONNREFUSED. It always reports false on other platforms, so code
+// handling them does not need build constraints.
+func WindowsErrorCode(err error) (uint32, bool) {
+	if runtime.GOOS != "windows" {
+		return 0, false
+	}
+	errno, ok := Errno(err)
+	return uint32(errno), ok
+}
+
+// ErrnoCategory returns the category of the system error number errno of
+// the running platform, classified the same way on Unix and Windows: a
+// missing file is CategoryNotFound whether it is ENOENT or
+// ERROR_FILE_NOT_FOUND. It reports false for numbers it does not know.
+func ErrnoCategory(errno syscall.Errno) (Category, bool) {
+	cat, ok := errnoCategories[errno]
+	return cat, ok
+}
+
+// errnoCategoryOf returns the category of the first system error number
+// in the chain of err.
+func errnoCategoryOf(err error) (Category, bool) {
+	errno, ok := Errno(err)
+	if !ok {
+		return "", false
+	}
+	return ErrnoCategory(errno)
+}
@@ -0,0 +1,25 @@
+//go:build plan9
+
+package sneterr
+
+import "syscall"
+
+// Errno reports false: Plan 9 reports system errors as strings.
+func Errno(err error) (syscall.Errno, bool) {
+	return 0, false
+}
+
+// WindowsErrorCode reports false, as on every platform other than Windows.
+func WindowsErrorCode(err error) (uint32, bool) {
+	return 0, false
+}
+
+// ErrnoCategory reports false: Plan 9 has no system error numbers.
+func ErrnoCategory(errno syscall.Errno) (Category, bool) {
+	return "", false
+}
+
+// errnoCategoryOf reports false: Plan 9 reports system errors as strings.
+func errnoCategoryOf(err error) (Category, bool) {
+	return "", false
+}
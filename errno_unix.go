@@ -0,0 +1,35 @@
+//go:build unix
+
+package sneterr
+
+import "syscall"
+
+func init() {
+	for errno, cat := range map[syscall.Errno]Category{
+		syscall.ENOENT:       CategoryNotFound,
+		syscall.ENOTDIR:      CategoryNotFound,
+		syscall.EACCES:       CategoryPermissionDenied,
+		syscall.EPERM:        CategoryPermissionDenied,
+		syscall.EROFS:        CategoryPermissionDenied,
+		syscall.EEXIST:       CategoryConflict,
+		syscall.EBUSY:        CategoryConflict,
+		syscall.EINVAL:       CategoryInvalidArgument,
+		syscall.ENAMETOOLONG: CategoryInvalidArgument,
+		syscall.ENOSPC:       CategoryResourceExhausted,
+		syscall.ENOMEM:       CategoryResourceExhausted,
+		syscall.EMFILE:       CategoryResourceExhausted,
+		syscall.ENFILE:       CategoryResourceExhausted,
+		syscall.ETIMEDOUT:    CategoryTimeout,
+		syscall.EINTR:        CategoryCanceled,
+		syscall.ECONNREFUSED: CategoryUnavailable,
+		syscall.ECONNRESET:   CategoryUnavailable,
+		syscall.ECONNABORTED: CategoryUnavailable,
+		syscall.EHOSTUNREACH: CategoryUnavailable,
+		syscall.ENETUNREACH:  CategoryUnavailable,
+		syscall.ENETDOWN:     CategoryUnavailable,
+		syscall.EPIPE:        CategoryUnavailable,
+		syscall.EAGAIN:       CategoryUnavailable,
+	} {
+		errnoCategories[errno] = cat
+	}
+}
@@ -0,0 +1,44 @@
+//go:build windows
+
+package sneterr
+
+import "syscall"
+
+func init() {
+	// Not every code has a constant in package syscall.
+	for errno, cat := range map[syscall.Errno]Category{
+		2:     CategoryNotFound,          // ERROR_FILE_NOT_FOUND
+		3:     CategoryNotFound,          // ERROR_PATH_NOT_FOUND
+		5:     CategoryPermissionDenied,  // ERROR_ACCESS_DENIED
+		19:    CategoryPermissionDenied,  // ERROR_WRITE_PROTECT
+		32:    CategoryConflict,          // ERROR_SHARING_VIOLATION
+		33:    CategoryConflict,          // ERROR_LOCK_VIOLATION
+		80:    CategoryConflict,          // ERROR_FILE_EXISTS
+		145:   CategoryConflict,          // ERROR_DIR_NOT_EMPTY
+		183:   CategoryConflict,          // ERROR_ALREADY_EXISTS
+		87:    CategoryInvalidArgument,   // ERROR_INVALID_PARAMETER
+		123:   CategoryInvalidArgument,   // ERROR_INVALID_NAME
+		206:   CategoryInvalidArgument,   // ERROR_FILENAME_EXCED_RANGE
+		4:     CategoryResourceExhausted, // ERROR_TOO_MANY_OPEN_FILES
+		8:     CategoryResourceExhausted, // ERROR_NOT_ENOUGH_MEMORY
+		14:    CategoryResourceExhausted, // ERROR_OUTOFMEMORY
+		39:    CategoryResourceExhausted, // ERROR_HANDLE_DISK_FULL
+		112:   CategoryResourceExhausted, // ERROR_DISK_FULL
+		121:   CategoryTimeout,           // ERROR_SEM_TIMEOUT
+		1460:  CategoryTimeout,           // ERROR_TIMEOUT
+		10060: CategoryTimeout,           // WSAETIMEDOUT
+		995:   CategoryCanceled,          // ERROR_OPERATION_ABORTED
+		1223:  CategoryCanceled,          // ERROR_CANCELLED
+		10004: CategoryCanceled,          // WSAEINTR
+		109:   CategoryUnavailable,       // ERROR_BROKEN_PIPE
+		10035: CategoryUnavailable,       // WSAEWOULDBLOCK
+		10050: CategoryUnavailable,       // WSAENETDOWN
+		10051: CategoryUnavailable,       // WSAENETUNREACH
+		10053: CategoryUnavailable,       // WSAECONNABORTED
+		10054: CategoryUnavailable,       // WSAECONNRESET
+		10061: CategoryUnavailable,       // WSAECONNREFUSED
+		10065: CategoryUnavailable,       // WSAEHOSTUNREACH
+	} {
+		errnoCategories[errno] = cat
+	}
+}
@@ -2,11 +2,16 @@
 package sneterr
 
 import (
-	"fmt"
-	"path"
-	"runtime"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrCodeUnknown is the code given to errors which did not carry one when
+// they had to be converted into an Error.
+const ErrCodeUnknown = "Unknown"
+
 // An Error wraps lower level errors with code, message and an original error.
 type Error interface {
 	// Satisfy the generic error interface.
@@ -27,14 +32,25 @@ type Error interface {
 // Both extra and origErr are optional.  If they are included their lines
 // will be added, but if they are not included their lines will be ignored.
 func SprintError(code, message, extra string, origErr error) string {
-	msg := fmt.Sprintf("%s: %s", code, message)
+	var causaErro string
+	if origErr != nil {
+		causaErro = origErr.Error()
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(code) + len(message) + len(extra) + len(causaErro) + 16)
+	sb.WriteString(code)
+	sb.WriteString(": ")
+	sb.WriteString(message)
 	if extra != "" {
-		msg = fmt.Sprintf("%s\n\t%s", msg, extra)
+		sb.WriteString("\n\t")
+		sb.WriteString(extra)
 	}
 	if origErr != nil {
-		msg = fmt.Sprintf("%s\ncaused by: %s", msg, origErr.Error())
+		sb.WriteString("\ncaused by: ")
+		sb.WriteString(causaErro)
 	}
-	return msg
+	return sb.String()
 }
 
 // A baseError wraps the code and message which defines an error. It also
@@ -49,9 +65,44 @@ type baseError struct {
 	// Detailed information about error
 	message string
 
+	// Message rendered on first use instead of message, see Newf
+	lazy *lazyMessage
+
 	// Optional original error. O que causou o erro
 	err error
 
+	// Optional structured data describing the error
+	fields Fields
+
+	// Whether message was rendered from the template of code, with
+	// fields as data
+	templated bool
+
+	// Optional HTTP status overriding the one registered for the code
+	status int
+
+	// Call stack where the error was created
+	stack []uintptr
+
+	// Fingerprint computed by the process which created the error, when
+	// it was received from another process
+	fingerprint string
+
+	// When the error was created
+	time time.Time
+
+	// How long the failed operation took, if known
+	duration time.Duration
+
+	// Whether the error was received from another process, see Frozen
+	frozen bool
+
+	// Whether the error only annotates a frozen error it wraps
+	annotated bool
+
+	// Renderings of an error returned by Define
+	cache *textCache
+
 	file string
 	line int
 }
@@ -79,12 +130,38 @@ func newBaseError(code, message string, origErr error, file string, line int) *b
 //
 // Satisfies the error interface.
 func (b baseError) Error() string {
+	if b.cache != nil {
+		return b.cache.error(b)
+	}
+	return b.render()
+}
+
+// render returns the string representation of the error.
+func (b baseError) render() string {
+	msg := b.Message()
 	var causaErro string
 	if b.err != nil {
 		causaErro = b.err.Error()
 	}
-	return fmt.Sprintf("(%s:%d) (code:%s) (msg:%s) (err:%s)",
-		b.file, b.line, b.code, b.message, causaErro)
+
+	var sb strings.Builder
+	sb.Grow(len(b.file) + len(b.code) + len(msg) + len(causaErro) + 48)
+	if b.file != "" {
+		var line [20]byte
+		sb.WriteByte('(')
+		sb.WriteString(b.file)
+		sb.WriteByte(':')
+		sb.Write(strconv.AppendInt(line[:0], int64(b.line), 10))
+		sb.WriteString(") ")
+	}
+	sb.WriteString("(code:")
+	sb.WriteString(b.code)
+	sb.WriteString(") (msg:")
+	sb.WriteString(msg)
+	sb.WriteString(") (err:")
+	sb.WriteString(causaErro)
+	sb.WriteByte(')')
+	return sb.String()
 }
 
 // String returns the string representation of the error.
@@ -100,21 +177,98 @@ func (b baseError) Code() string {
 
 // Message returns the error details message.
 func (b baseError) Message() string {
+	if b.lazy != nil {
+		return b.lazy.String()
+	}
 	return b.message
 }
 
-//OrigErr ...
+// OrigErr ...
 func (b baseError) OrigErr() error {
 	return b.err
 }
 
-// New returns an Error object described by the code, message, and origErr.
+// Unwrap returns the original error so the standard errors package can
+// inspect the chain.
+func (b baseError) Unwrap() error {
+	return b.err
+}
+
+// derive returns a copy of err to build a new error from. If err is not a
+// *baseError the copy wraps it, with ErrCodeUnknown if err does not satisfy
+// the Error interface. A frozen error is wrapped too, by an annotation
+// with the same code and message, see Frozen.
+func derive(err error) *baseError {
+	switch e := err.(type) {
+	case *baseError:
+		if e.frozen {
+			return &baseError{
+				code:        e.code,
+				message:     e.Message(),
+				err:         e,
+				fingerprint: e.fingerprint,
+				annotated:   true,
+			}
+		}
+		b := *e
+		b.cache = nil
+		return &b
+	case Error:
+		b := &baseError{code: e.Code(), message: e.Message(), err: e}
+		if Frozen(e) {
+			var f *baseError
+			if errors.As(e, &f) {
+				b.fingerprint = f.fingerprint
+			}
+			b.annotated = true
+		}
+		return b
+	default:
+		return &baseError{code: ErrCodeUnknown, message: err.Error(), err: err}
+	}
+}
+
+// New returns an Error with the code and message, configured by opts:
+//
+//	err := sneterr.New("OrderNotFound", "order was not found",
+//		sneterr.WithCause(err),
+//		sneterr.WithField("order_id", id),
+//		sneterr.WithStatus(http.StatusNotFound))
 //
-// If origErr satisfies the Error interface it will not be wrapped within a new
-// Error object and will instead be returned.
-func New(code, message string, origErr error) Error {
-	_, file, line, _ := runtime.Caller(1)
-	_, nomeArquivo := path.Split(file)
+// The returned Error is immutable: With, WithFields and WithDuration
+// return modified copies of it.
+func New(code, message string, opts ...Option) Error {
+	return newError(2, code, message, nil, opts...)
+}
 
-	return newBaseError(code, message, origErr, nomeArquivo, line)
+// Wrap returns an Error wrapping err with the code and message. If err is
+// nil Wrap returns nil.
+func Wrap(err error, code, message string, opts ...Option) Error {
+	if err == nil {
+		return nil
+	}
+	return newError(2, code, message, err, opts...)
+}
+
+// newError records the caller skip frames above it and notifies the
+// registered hooks about the new error, once opts are applied.
+func newError(skip int, code, message string, origErr error, opts ...Option) *baseError {
+	b := newBaseError(code, message, origErr, "", 0)
+	b.apply(opts)
+	return finishError(skip+1, b)
+}
+
+// finishError records the caller skip frames above it as the location of b
+// and notifies the registered hooks about it.
+func finishError(skip int, b *baseError) *baseError {
+	b.time = time.Now()
+	if b.fields[FieldDebug] == true {
+		b.stack = allCallers(skip)
+	} else {
+		b.stack = callers(skip)
+	}
+	b.file, b.line = location(b.stack)
+
+	runHooks(b)
+	return b
 }
@@ -0,0 +1,113 @@
+package sneterr
+
+import (
+	"context"
+	"time"
+)
+
+// A DomainEvent is a business event emitted for a failure, such as
+// "payment.failed", for analytics.
+type DomainEvent struct {
+	// Name of the event.
+	Name string `json:"name"`
+
+	// Code, message and fields of the error the event is emitted for.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Fields  Fields `json:"fields,omitempty"`
+
+	// When the error occurred.
+	OccurredAt time.Time `json:"occurredAt"`
+
+	// Fingerprint of the error, to group the events of the same failure.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// A Publisher publishes domain events, for instance to a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, event DomainEvent) error
+}
+
+// An EventMapper converts errors into domain events according to a
+// policy, and publishes them:
+//
+//	events := sneterr.MustNewEventMapper(broker, map[string]string{
+//		"payment.*":         "payment.failed",
+//		"InvalidTransition": "order.rejected",
+//	})
+//	sneterr.RegisterHook(events.Hook(nil))
+//
+// An EventMapper is safe for concurrent use.
+type EventMapper struct {
+	events *CodePolicy[string]
+	pub    Publisher
+}
+
+// NewEventMapper returns an EventMapper publishing with pub the events of
+// the errors whose code matches a pattern of events, as in a CodePolicy,
+// named by its value. It fails if a pattern is invalid.
+func NewEventMapper(pub Publisher, events map[string]string) (*EventMapper, error) {
+	p, err := CompilePolicy(events)
+	if err != nil {
+		return nil, err
+	}
+	return &EventMapper{events: p, pub: pub}, nil
+}
+
+// MustNewEventMapper is like NewEventMapper but panics if a pattern is
+// invalid.
+func MustNewEventMapper(pub Publisher, events map[string]string) *EventMapper {
+	m, err := NewEventMapper(pub, events)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Event returns the domain event for the first Error in the chain of err,
+// and whether its code is mapped to one.
+func (m *EventMapper) Event(err error) (DomainEvent, bool) {
+	e, ok := As[Error](err)
+	if !ok {
+		return DomainEvent{}, false
+	}
+	name, ok := m.events.Match(e.Code())
+	if !ok || name == "" {
+		return DomainEvent{}, false
+	}
+	return DomainEvent{
+		Name:        name,
+		Code:        e.Code(),
+		Message:     e.Message(),
+		Fields:      FieldsOf(e),
+		OccurredAt:  OccurredAt(e),
+		Fingerprint: Fingerprint(e),
+	}, true
+}
+
+// Emit publishes the domain event for err, if its code is mapped to one,
+// and returns the error of the publisher.
+func (m *EventMapper) Emit(ctx context.Context, err error) error {
+	ev, ok := m.Event(err)
+	if !ok {
+		return nil
+	}
+	return m.pub.Publish(ctx, ev)
+}
+
+// Hook returns a Hook emitting the domain events of the errors as they are
+// created, so handlers need no plumbing. Publishers should not block, as
+// hooks run on the goroutine creating the error. The errors of the
+// publisher are passed to onError, unless it is nil; onError must not
+// create errors through this package.
+func (m *EventMapper) Hook(onError func(DomainEvent, error)) Hook {
+	return func(err Error) {
+		ev, ok := m.Event(err)
+		if !ok {
+			return
+		}
+		if pErr := m.pub.Publish(context.Background(), ev); pErr != nil && onError != nil {
+			onError(ev, pErr)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package sneterr
+
+import "errors"
+
+// Fields holds structured data attached to an error.
+type Fields map[string]interface{}
+
+// Fields returns a copy of the fields attached to the error.
+func (b baseError) Fields() Fields {
+	return b.fields.clone()
+}
+
+// clone returns a copy of f, or nil if f is empty.
+func (f Fields) clone() Fields {
+	if len(f) == 0 {
+		return nil
+	}
+	c := make(Fields, len(f))
+	for k, v := range f {
+		c[k] = v
+	}
+	return c
+}
+
+// WithFields returns a copy of err carrying fields in addition to the ones
+// already attached. Fields with the same key are replaced. URLs and HTTP
+// requests are redacted, see SetURLRedactor. In dev mode the fields are
+// checked against the schema of the code, see SetDevMode.
+//
+// If err does not satisfy the Error interface it is wrapped as the original
+// error of a new Error with ErrCodeUnknown. If err is nil WithFields returns
+// nil.
+func WithFields(err error, fields Fields) Error {
+	if err == nil {
+		return nil
+	}
+
+	b := derive(err)
+	checkFields(b.code, fields)
+
+	merged := make(Fields, len(b.fields)+len(fields))
+	for k, v := range b.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = redactField(v)
+	}
+	b.fields = merged
+	return b
+}
+
+// FieldsOf returns the fields attached to err and the errors it wraps.
+// When the same key is set at several levels the outermost value wins.
+func FieldsOf(err error) Fields {
+	var out Fields
+	for ; err != nil; err = errors.Unwrap(err) {
+		f, ok := err.(interface{ Fields() Fields })
+		if !ok {
+			continue
+		}
+		for k, v := range f.Fields() {
+			if out == nil {
+				out = Fields{}
+			}
+			if _, set := out[k]; !set {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// matchedFields returns the fields of e, an error matched by code in a
+// chain. An error without a Fields method, such as the RequestFailure
+// returned by FromResponse which embeds the decoded error, gets the fields
+// of the errors it wraps, as merged by FieldsOf.
+func matchedFields(e error) Fields {
+	if f, ok := e.(interface{ Fields() Fields }); ok {
+		return f.Fields()
+	}
+	return FieldsOf(e)
+}
@@ -0,0 +1,80 @@
+package sneterr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// fingerprintFrames is the number of stack frames hashed by Fingerprint.
+const fingerprintFrames = 3
+
+// Fingerprint returns a stable hash identifying the kind of failure err
+// represents, for grouping occurrences of the same error in alerting tools.
+//
+// The hash covers the code of err, the type of its root cause and the
+// functions of the top frames of the stack where the innermost Error of the
+// chain was created. Messages, fields and line numbers are left out so
+// variable details do not split a group. An error received from another
+// process keeps the fingerprint computed there. A nil error has an empty
+// fingerprint.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	if b, ok := err.(*baseError); ok && b.fingerprint != "" {
+		return b.fingerprint
+	}
+
+	h := sha256.New()
+	code := ErrCodeUnknown
+	if e, ok := err.(Error); ok {
+		code = e.Code()
+	}
+	io.WriteString(h, code)
+
+	var stack []uintptr
+	root := err
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if b, ok := e.(*baseError); ok && len(b.stack) > 0 {
+			stack = b.stack
+		}
+		root = e
+	}
+	fmt.Fprintf(h, "\x00%T", root)
+
+	for _, f := range frames(stack, fingerprintFrames) {
+		io.WriteString(h, "\x00")
+		io.WriteString(h, f.Function)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// DedupKey returns a key identifying the occurrences of the failure err
+// represents within the same window of time, for the events sent to
+// external trackers and webhooks: redelivering an event, or reporting the
+// same failure from several replicas, yields the same key, so the tracker
+// can drop the duplicates.
+//
+// The key combines the Fingerprint of err with the index of the window
+// containing the time err occurred at, as returned by OccurredAt, or the
+// current time if err has none. Windows are counted from the Unix epoch,
+// to the nanosecond, so windows shorter than a second are honored. A
+// window of zero or less leaves the time out of the key. A nil error has
+// an empty key.
+func DedupKey(err error, window time.Duration) string {
+	fp := Fingerprint(err)
+	if fp == "" || window <= 0 {
+		return fp
+	}
+
+	at := OccurredAt(err)
+	if at.IsZero() {
+		at = time.Now()
+	}
+	return fp + "-" + strconv.FormatInt(at.UnixNano()/int64(window), 10)
+}
@@ -0,0 +1,37 @@
+package sneterr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupKeyWindow(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(d time.Duration) Error {
+		b := newBaseError("Timeout", "timed out", nil, "", 0)
+		b.time = base.Add(d)
+		return b
+	}
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		a, b   time.Duration
+		same   bool
+	}{
+		{"same second", time.Second, 0, 999 * time.Millisecond, true},
+		{"next second", time.Second, 999 * time.Millisecond, time.Second, false},
+		{"same sub-second window", 100 * time.Millisecond, 10 * time.Millisecond, 90 * time.Millisecond, true},
+		{"next sub-second window", 100 * time.Millisecond, 90 * time.Millisecond, 110 * time.Millisecond, false},
+		{"same minute", time.Minute, 0, 54 * time.Second, true},
+		{"next minute", time.Minute, 54 * time.Second, 55 * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ka, kb := DedupKey(at(tt.a), tt.window), DedupKey(at(tt.b), tt.window)
+			if (ka == kb) != tt.same {
+				t.Errorf("DedupKey at %v = %q, at %v = %q, want same %v", tt.a, ka, tt.b, kb, tt.same)
+			}
+		})
+	}
+}
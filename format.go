@@ -0,0 +1,110 @@
+package sneterr
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// A Frame is the program counter of a stack frame where an error was
+// created. It has the representation of github.com/pkg/errors.Frame so
+// tools extracting stacks from pkg/errors, such as Sentry, recognize it.
+type Frame uintptr
+
+// Format formats the frame like github.com/pkg/errors.Frame:
+//
+//	%s    source file name
+//	%d    source line
+//	%n    function name
+//	%v    equivalent to %s:%d
+//	%+s   function name and path of source file, separated by \n\t
+//	%+v   equivalent to %+s:%d
+func (f Frame) Format(s fmt.State, verb rune) {
+	frame, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			io.WriteString(s, frame.Function)
+			io.WriteString(s, "\n\t")
+			io.WriteString(s, frame.File)
+			return
+		}
+		_, file := path.Split(frame.File)
+		io.WriteString(s, file)
+	case 'd':
+		io.WriteString(s, strconv.Itoa(frame.Line))
+	case 'n':
+		io.WriteString(s, funcName(frame.Function))
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// A StackTrace is the stack where an error was created, innermost frame
+// first. It has the representation of github.com/pkg/errors.StackTrace.
+type StackTrace []Frame
+
+// Format formats the stack like github.com/pkg/errors.StackTrace, %+v
+// printing one frame per line with its function, file and line.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		for _, f := range st {
+			io.WriteString(s, "\n")
+			f.Format(s, verb)
+		}
+	case verb == 'v' || verb == 's':
+		fmt.Fprintf(s, "%v", []Frame(st))
+	}
+}
+
+// StackTrace returns the stack where the error was created, leaving out
+// the leading frames of functions marked by Helper.
+func (b baseError) StackTrace() StackTrace {
+	pcs := trimHelpers(b.stack)
+	st := make(StackTrace, len(pcs))
+	for i, pc := range pcs {
+		st[i] = Frame(pc)
+	}
+	return st
+}
+
+// funcName removes the path prefix of the package from a function name.
+func funcName(name string) string {
+	_, name = path.Split(name)
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Format formats the error according to the fmt.Formatter interface.
+//
+//	%s, %v  the Error string
+//	%q      the quoted Error string
+//	%+v     the code and message of each error of the chain, innermost
+//	        first, each followed by the stack where it was created, in
+//	        the format of github.com/pkg/errors
+func (b baseError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if b.err != nil {
+				fmt.Fprintf(s, "%+v\n", b.err)
+			}
+			io.WriteString(s, SprintError(b.code, b.Message(), "", nil))
+			b.StackTrace().Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, b.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", b.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package sneterr
+
+// Frozen reports whether the error was rebuilt from its encoding, received
+// from another process.
+func (b baseError) Frozen() bool {
+	return b.frozen
+}
+
+// Annotated reports whether the error was added by the local process to a
+// frozen error it wraps.
+func (b baseError) Annotated() bool {
+	return b.annotated
+}
+
+// Frozen reports whether err, the outermost error of its chain, was
+// received from another process, as rebuilt by UnmarshalError or
+// FromResponse, and was not annotated since.
+//
+// The code, message and fields of a frozen error are attributed to the
+// service which created it. Enriching it, with With, WithFields,
+// WithDuration or the helpers built on them, would credit that service
+// with the local additions: instead they return a copy wrapping the frozen
+// error, with the same code and message, which carries them and is
+// reported by Annotated. Its JSON encoding marks it with an "annotated"
+// member, so the services further along can tell which service added
+// what.
+func Frozen(err error) bool {
+	f, ok := err.(interface{ Frozen() bool })
+	return ok && f.Frozen()
+}
+
+// Annotated reports whether err, the outermost error of its chain, is an
+// annotation added by the local process, or a process it was relayed
+// through, to a frozen error it wraps. See Frozen.
+func Annotated(err error) bool {
+	a, ok := err.(interface{ Annotated() bool })
+	return ok && a.Annotated()
+}
@@ -0,0 +1,61 @@
+package sneterr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestFrozenDecoded(t *testing.T) {
+	data, err := json.Marshal(New("OrderNotFound", "order was not found", WithField("order_id", 7)))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded, err := UnmarshalError(data)
+	if err != nil {
+		t.Fatalf("UnmarshalError() error = %v", err)
+	}
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}
+
+	tests := []struct {
+		name string
+		err  Error
+	}{
+		{name: "UnmarshalError", err: decoded},
+		{name: "FromResponse", err: FromResponse(resp)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !Frozen(tt.err) {
+				t.Fatalf("Frozen() = false, want true")
+			}
+			if Annotated(tt.err) {
+				t.Errorf("Annotated() = true, want false")
+			}
+
+			a := With(tt.err, WithField("attempt", 2))
+			if Frozen(a) || !Annotated(a) {
+				t.Errorf("after With, Frozen() = %v and Annotated() = %v, want false and true", Frozen(a), Annotated(a))
+			}
+			if a.Code() != "OrderNotFound" || a.Message() != "order was not found" {
+				t.Errorf("annotation is %s: %s, want OrderNotFound: order was not found", a.Code(), a.Message())
+			}
+			if got := a.(*baseError).fields; len(got) != 1 || got["attempt"] != 2 {
+				t.Errorf("annotation fields = %v, want only attempt", got)
+			}
+			if got := FieldsOf(a)["order_id"]; got == nil {
+				t.Errorf("field order_id of the frozen error is lost")
+			}
+			if Fingerprint(a) != Fingerprint(decoded) {
+				t.Errorf("Fingerprint() = %q, want the decoded %q", Fingerprint(a), Fingerprint(decoded))
+			}
+		})
+	}
+}
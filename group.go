@@ -0,0 +1,121 @@
+package sneterr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// A Group runs named tasks in goroutines and collects every failure.
+//
+// It mirrors golang.org/x/sync/errgroup, except that Wait reports all the
+// failed tasks instead of only the first one. Each failure carries the task
+// name in its "task" field. The zero Group is valid, has no limit on active
+// goroutines and does not cancel on error.
+type Group struct {
+	cancel func(error)
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	mu    sync.Mutex
+	tasks int
+	errs  []error
+}
+
+// GroupWithContext returns a new Group and an associated Context derived
+// from ctx.
+//
+// The derived Context is canceled the first time a task fails or the first
+// time Wait returns, whichever occurs first.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine, blocking until the goroutine can be added
+// without exceeding the limit set by SetLimit.
+func (g *Group) Go(name string, f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(name, f)
+}
+
+// TryGo calls f in a new goroutine only if the number of active goroutines
+// is below the limit set by SetLimit. It reports whether f was started.
+func (g *Group) TryGo(name string, f func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(name, f)
+	return true
+}
+
+// SetLimit limits the number of active goroutines in the group to at most
+// n. A negative value indicates no limit.
+//
+// The limit must not be modified while any goroutines in the group are
+// active.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("sneterr: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Wait blocks until all the tasks have returned, then returns a MultiError
+// with every failure, or nil if all of them succeeded.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d of %d tasks failed", len(g.errs), g.tasks)
+	return NewMultiError(ErrCodeMultiple, msg, g.errs)
+}
+
+func (g *Group) start(name string, f func() error) {
+	g.mu.Lock()
+	g.tasks++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+
+		if err := f(); err != nil {
+			err = WithFields(err, Fields{"task": name})
+
+			g.mu.Lock()
+			first := len(g.errs) == 0
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+
+			if first && g.cancel != nil {
+				g.cancel(err)
+			}
+		}
+	}()
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
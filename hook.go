@@ -0,0 +1,47 @@
+package sneterr
+
+import "sync"
+
+// A Hook is called with every Error created by New or Wrap.
+//
+// Hooks run synchronously on the goroutine creating the error, so they
+// should return quickly and must not create errors through this package.
+type Hook func(Error)
+
+var (
+	hooksMu sync.RWMutex
+	hooks   []Hook
+)
+
+// RegisterHook adds h to the hooks invoked when an Error is created.
+// Hooks are called in registration order.
+func RegisterHook(h Hook) {
+	if h == nil {
+		return
+	}
+	hooksMu.Lock()
+	hooks = append(hooks, h)
+	hooksMu.Unlock()
+}
+
+// runHooks calls every registered hook with err.
+func runHooks(err Error) {
+	hooksMu.RLock()
+	hs := hooks
+	hooksMu.RUnlock()
+
+	for _, h := range hs {
+		h(err)
+	}
+}
+
+// SeverityHook returns a Hook calling h only for errors whose severity is
+// at least min, for instance to keep serious errors in a separate journal
+// with a longer retention.
+func SeverityHook(min Severity, h Hook) Hook {
+	return func(err Error) {
+		if SeverityOf(err) >= min {
+			h(err)
+		}
+	}
+}
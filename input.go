@@ -0,0 +1,114 @@
+package sneterr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// FieldInputHash is the field key of the hash of the input which caused
+// an error, set by WithInputHash.
+const FieldInputHash = "input_hash"
+
+// WithInputHash returns a copy of err recording h, the hash of the input
+// which triggered the failure, typically computed by HashInput. Repeated
+// failures on the same input can then be told apart from failures on
+// different inputs across retries and replicas, without storing the input
+// itself. If err is nil WithInputHash returns nil.
+func WithInputHash(err error, h string) Error {
+	if err == nil {
+		return nil
+	}
+	return WithFields(err, Fields{FieldInputHash: h})
+}
+
+// InputHash returns the input hash recorded in the chain of err by
+// WithInputHash.
+func InputHash(err error) (string, bool) {
+	return Field[string](err, FieldInputHash)
+}
+
+// HashInput returns the hex encoded SHA-256 hash of payload, for
+// WithInputHash.
+//
+// A payload which is a value encodable as JSON, or a []byte or string
+// holding a JSON document, is hashed in a canonical form where object
+// members are sorted, so equivalent documents have the same hash. Numbers
+// are kept as written, so large integers such as IDs or amounts in minor
+// units are not rounded to the same value. The members named in redact,
+// compared case-insensitively at any depth, are left out: they should
+// include the secrets whose hash could be guessed by brute force, such as
+// card numbers, and the values changing between retries of the same
+// input, such as timestamps or nonces. Other []byte and string payloads
+// are hashed as they are.
+//
+// HashInput returns "" for a payload which cannot be encoded as JSON,
+// rather than a hash shared by all of them.
+func HashInput(payload interface{}, redact ...string) string {
+	var data []byte
+	switch p := payload.(type) {
+	case []byte:
+		data = p
+	case string:
+		data = []byte(p)
+	default:
+		var err error
+		if data, err = json.Marshal(p); err != nil {
+			return ""
+		}
+	}
+
+	if doc, ok := decodeDoc(data); ok {
+		keys := make(map[string]bool, len(redact))
+		for _, k := range redact {
+			keys[strings.ToLower(k)] = true
+		}
+		if canonical, err := json.Marshal(redactDoc(doc, keys)); err == nil {
+			data = canonical
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// decodeDoc decodes data, a single JSON document, keeping its numbers as
+// json.Number.
+func decodeDoc(data []byte) (interface{}, bool) {
+	if data == nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if dec.Decode(&doc) != nil {
+		return nil, false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+	return doc, true
+}
+
+// redactDoc removes the members named in keys from the objects of the
+// decoded JSON document v.
+func redactDoc(v interface{}, keys map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, c := range v {
+			if keys[strings.ToLower(k)] {
+				delete(v, k)
+				continue
+			}
+			v[k] = redactDoc(c, keys)
+		}
+	case []interface{}:
+		for i, c := range v {
+			v[i] = redactDoc(c, keys)
+		}
+	}
+	return v
+}
@@ -0,0 +1,36 @@
+package sneterr
+
+import "testing"
+
+func TestHashInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   interface{}
+		redact []string
+		same   bool
+	}{
+		{"member order", `{"a":1,"b":2}`, `{"b":2,"a":1}`, nil, true},
+		{"large integers", `{"id":9007199254740993}`, `{"id":9007199254740992}`, nil, false},
+		{"large integers as values", map[string]uint64{"id": 1<<53 + 1}, map[string]uint64{"id": 1 << 53}, nil, false},
+		{"redacted member", `{"id":1,"Nonce":"x"}`, `{"nonce":"y","id":1}`, []string{"nonce"}, true},
+		{"nested redacted member", `{"card":{"number":"4111"}}`, `{"card":{"number":"4242"}}`, []string{"number"}, true},
+		{"trailing data", `{"a":1} {"b":2}`, `{"a":1}`, nil, false},
+		{"not JSON", "a=1", "a=2", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ha, hb := HashInput(tt.a, tt.redact...), HashInput(tt.b, tt.redact...)
+			if (ha == hb) != tt.same {
+				t.Errorf("HashInput(%v) = %s, HashInput(%v) = %s, want same %v", tt.a, ha, tt.b, hb, tt.same)
+			}
+		})
+	}
+}
+
+func TestHashInputUnencodable(t *testing.T) {
+	for _, p := range []interface{}{make(chan int), func() {}} {
+		if h := HashInput(p); h != "" {
+			t.Errorf("HashInput(%T) = %s, want empty", p, h)
+		}
+	}
+}
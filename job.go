@@ -0,0 +1,245 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A JobResult is the outcome of a run of a scheduled job, reported
+// uniformly whatever the job.
+type JobResult struct {
+	// Name of the job.
+	Job string
+
+	// When the run started.
+	Started time.Time
+
+	// How long the run took.
+	Duration time.Duration
+
+	// Why the run failed, or nil if it succeeded.
+	Err Error
+
+	// Counters maintained by the job, such as the number of rows
+	// processed.
+	Counters map[string]int64
+
+	// Warnings raised during the run.
+	Warnings []Warning
+
+	// Outcome of the records processed by the run, if it recorded any.
+	Records *JobReport
+}
+
+// Succeeded reports whether the run succeeded.
+func (r JobResult) Succeeded() bool {
+	return r.Err == nil
+}
+
+// Status returns "succeeded" or "failed".
+func (r JobResult) Status() string {
+	if r.Err != nil {
+		return "failed"
+	}
+	return "succeeded"
+}
+
+// String returns a one line summary of the run, such as
+//
+//	sync-orders succeeded in 1.5s (rows=1200 skipped=3) with 1 warning
+func (r JobResult) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s in %v", r.Job, r.Status(), r.Duration.Round(time.Millisecond))
+	if len(r.Counters) > 0 {
+		sb.WriteString(" (")
+		for i, name := range r.counterNames() {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			fmt.Fprintf(&sb, "%s=%d", name, r.Counters[name])
+		}
+		sb.WriteByte(')')
+	}
+	if r.Records != nil {
+		fmt.Fprintf(&sb, " records succeeded=%d failed=%d", r.Records.Succeeded, r.Records.Failed)
+	}
+	switch n := len(r.Warnings); n {
+	case 0:
+	case 1:
+		sb.WriteString(" with 1 warning")
+	default:
+		fmt.Fprintf(&sb, " with %d warnings", n)
+	}
+	if r.Err != nil {
+		fmt.Fprintf(&sb, ": %s: %s", r.Err.Code(), r.Err.Message())
+	}
+	return sb.String()
+}
+
+// counterNames returns the names of the counters in alphabetical order.
+func (r JobResult) counterNames() []string {
+	names := make([]string, 0, len(r.Counters))
+	for name := range r.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LogValue returns the result as a slog group.
+func (r JobResult) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("job", r.Job),
+		slog.String("status", r.Status()),
+		slog.Time("started", r.Started),
+		slog.Duration("duration", r.Duration),
+	}
+	if len(r.Counters) > 0 {
+		counters := make([]slog.Attr, 0, len(r.Counters))
+		for _, name := range r.counterNames() {
+			counters = append(counters, slog.Int64(name, r.Counters[name]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "counters", Value: slog.GroupValue(counters...)})
+	}
+	if len(r.Warnings) > 0 {
+		attrs = append(attrs, slog.Int("warnings", len(r.Warnings)))
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.Any("error", r.Err))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// jobResultJSON is the JSON representation of a JobResult.
+type jobResultJSON struct {
+	Job      string           `json:"job"`
+	Status   string           `json:"status"`
+	Started  time.Time        `json:"started"`
+	Duration string           `json:"duration"`
+	Error    *jsonError       `json:"error,omitempty"`
+	Counters map[string]int64 `json:"counters,omitempty"`
+	Warnings []Warning        `json:"warnings,omitempty"`
+	Records  *JobReport       `json:"records,omitempty"`
+}
+
+// MarshalJSON encodes the result as JSON for the job dashboard.
+func (r JobResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jobResultJSON{
+		Job:      r.Job,
+		Status:   r.Status(),
+		Started:  r.Started,
+		Duration: r.Duration.String(),
+		Error:    toJSON(r.Err),
+		Counters: r.Counters,
+		Warnings: r.Warnings,
+		Records:  r.Records,
+	})
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON.
+func (r *JobResult) UnmarshalJSON(data []byte) error {
+	var j jobResultJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(j.Duration)
+	if err != nil && j.Duration != "" {
+		return err
+	}
+
+	*r = JobResult{
+		Job:      j.Job,
+		Started:  j.Started,
+		Duration: d,
+		Counters: j.Counters,
+		Warnings: j.Warnings,
+		Records:  j.Records,
+	}
+	if j.Error != nil {
+		e, ok := fromJSON(j.Error).(Error)
+		if !ok {
+			e = newBaseError(ErrCodeUnknown, j.Error.Message, nil, "", 0)
+		}
+		r.Err = e
+	}
+	return nil
+}
+
+// A JobRun records the outcome of a run of a job as it progresses. It is
+// safe for concurrent use.
+type JobRun struct {
+	job     string
+	started time.Time
+	records JobSummary
+
+	mu       sync.Mutex
+	counters map[string]int64
+	warnings []Warning
+	recorded bool
+}
+
+// StartJob starts recording a run of the job name.
+func StartJob(name string) *JobRun {
+	return &JobRun{job: name, started: time.Now(), counters: map[string]int64{}}
+}
+
+// Count adds n to the counter name.
+func (j *JobRun) Count(name string, n int64) {
+	j.mu.Lock()
+	j.counters[name] += n
+	j.mu.Unlock()
+}
+
+// Warn records the warning w.
+func (j *JobRun) Warn(w Warning) {
+	j.mu.Lock()
+	j.warnings = append(j.warnings, w)
+	j.mu.Unlock()
+}
+
+// AddRecord records the outcome of one record processed by the run, as
+// JobSummary.Add does.
+func (j *JobRun) AddRecord(err error) {
+	j.mu.Lock()
+	j.recorded = true
+	j.mu.Unlock()
+	j.records.Add(err)
+}
+
+// Finish returns the result of the run, which failed with err unless err
+// is nil. An err which does not satisfy the Error interface is wrapped
+// with ErrCodeUnknown.
+func (j *JobRun) Finish(err error) JobResult {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	r := JobResult{
+		Job:      j.job,
+		Started:  j.started,
+		Duration: time.Since(j.started),
+		Warnings: append([]Warning(nil), j.warnings...),
+	}
+	if len(j.counters) > 0 {
+		r.Counters = make(map[string]int64, len(j.counters))
+		for name, n := range j.counters {
+			r.Counters[name] = n
+		}
+	}
+	if j.recorded {
+		report := j.records.Report()
+		r.Records = &report
+	}
+	if err != nil {
+		e, ok := err.(Error)
+		if !ok {
+			e = newError(2, ErrCodeUnknown, err.Error(), err)
+		}
+		r.Err = e
+	}
+	return r
+}
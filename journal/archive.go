@@ -0,0 +1,93 @@
+package journal
+
+import (
+	"os"
+	"sync"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// An Archive buffers error events in memory and stores each batch as a
+// single sealed segment when flushed, for long term storage where a Journal
+// would produce too many small files. Options.MaxSegmentBytes bounds the
+// size of the buffer: reaching it flushes the batch.
+//
+// An Archive is safe for concurrent use.
+type Archive struct {
+	dir  string
+	opts Options
+
+	mu  sync.Mutex
+	seq uint64
+	buf []byte
+}
+
+// OpenArchive returns an Archive storing segments in dir, creating it if
+// needed.
+func OpenArchive(dir string, opts Options) (*Archive, error) {
+	j, err := Open(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{dir: j.dir, opts: j.opts, seq: j.seq}, nil
+}
+
+// Write adds the JSON encoding of err to the current batch.
+func (a *Archive) Write(err sneterr.Error) error {
+	return writeError(a, err)
+}
+
+// WriteEvent adds an already encoded event line to the current batch.
+func (a *Archive) WriteEvent(line []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.buf = append(a.buf, line...)
+	if int64(len(a.buf)) >= a.opts.MaxSegmentBytes {
+		return a.flush()
+	}
+	return nil
+}
+
+// Hook returns a sneterr.Hook adding every created error to the archive.
+// Write failures are dropped.
+func (a *Archive) Hook() sneterr.Hook {
+	return func(err sneterr.Error) {
+		a.Write(err)
+	}
+}
+
+// Flush stores the current batch as a sealed segment. It does nothing if
+// the batch is empty.
+func (a *Archive) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.flush()
+}
+
+func (a *Archive) flush() error {
+	if len(a.buf) == 0 {
+		return nil
+	}
+
+	seg, err := createSegment(a.dir, a.seq, a.opts.Compressor)
+	if err != nil {
+		return err
+	}
+	a.seq++
+	if err := seg.write(a.buf); err != nil {
+		seg.file.Close()
+		os.Remove(seg.path)
+		return err
+	}
+	if err := seg.seal(); err != nil {
+		return err
+	}
+	a.buf = a.buf[:0]
+	return nil
+}
+
+// Close flushes the current batch.
+func (a *Archive) Close() error {
+	return a.Flush()
+}
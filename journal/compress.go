@@ -0,0 +1,75 @@
+package journal
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// A Compressor compresses the segments written by a Journal or an Archive.
+type Compressor interface {
+	// Returns the extension appended to the name of compressed segments,
+	// such as ".gz".
+	Extension() string
+
+	// Returns a writer compressing into w. Closing it flushes the
+	// compressed stream but does not close w.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+
+	// Returns a reader decompressing r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// Gzip compresses segments with gzip at the default compression level.
+var Gzip Compressor = GzipLevel(gzip.DefaultCompression)
+
+// GzipLevel returns a Compressor using gzip at the given level.
+func GzipLevel(level int) Compressor {
+	return gzipCompressor{level: level}
+}
+
+type gzipCompressor struct {
+	level int
+}
+
+func (gzipCompressor) Extension() string {
+	return ".gz"
+}
+
+func (c gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, c.level)
+}
+
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// nopCompressor writes segments uncompressed.
+type nopCompressor struct{}
+
+func (nopCompressor) Extension() string {
+	return ""
+}
+
+func (nopCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (nopCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// compressor returns c, or the uncompressed Compressor if c is nil.
+func compressor(c Compressor) Compressor {
+	if c == nil {
+		return nopCompressor{}
+	}
+	return c
+}
@@ -0,0 +1,140 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Retention bounds how long and how much sealed segments are kept. Zero
+// values mean no limit.
+//
+// Retention applies to whole segments. To retain errors of some severities
+// longer, write them to a separate Journal through sneterr.SeverityHook and
+// give it its own Janitor.
+type Retention struct {
+	// Maximum age of a segment, measured from when it was sealed.
+	MaxAge time.Duration
+
+	// Maximum total size of the segments on disk. The oldest segments are
+	// removed first.
+	MaxBytes int64
+}
+
+// JanitorStats reports the segments kept by a Janitor and its work.
+type JanitorStats struct {
+	// Number of sealed segments kept after the last run.
+	Segments int
+
+	// Size on disk of the segments kept after the last run.
+	Bytes int64
+
+	// Number of segments removed so far.
+	Removed int64
+
+	// When the retention was last enforced.
+	LastRun time.Time
+}
+
+// A Janitor enforces a Retention on the sealed segments of a Journal or
+// Archive directory. It is safe for concurrent use.
+type Janitor struct {
+	dir       string
+	retention Retention
+
+	mu    sync.Mutex
+	stats JanitorStats
+}
+
+// NewJanitor returns a Janitor enforcing r on the segments in dir.
+func NewJanitor(dir string, r Retention) *Janitor {
+	return &Janitor{dir: dir, retention: r}
+}
+
+// Run removes the sealed segments exceeding the retention, along with
+// their checksum files. Segments still being written are never removed.
+func (j *Janitor) Run() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	paths, err := Segments(j.dir)
+	if err != nil {
+		return fmt.Errorf("journal: list segments: %w", err)
+	}
+
+	type segment struct {
+		path string
+		size int64
+		at   time.Time
+	}
+	segs := make([]segment, 0, len(paths))
+	var total int64
+	for _, p := range paths {
+		info, err := os.Stat(p + checksumSuffix)
+		if err != nil {
+			continue
+		}
+		data, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		segs = append(segs, segment{path: p, size: data.Size(), at: info.ModTime()})
+		total += data.Size()
+	}
+
+	now := time.Now()
+	var errs []error
+	for len(segs) > 0 {
+		s := segs[0]
+		expired := j.retention.MaxAge > 0 && now.Sub(s.at) > j.retention.MaxAge
+		oversize := j.retention.MaxBytes > 0 && total > j.retention.MaxBytes
+		if !expired && !oversize {
+			break
+		}
+		if err := errors.Join(os.Remove(s.path), os.Remove(s.path+checksumSuffix)); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		total -= s.size
+		segs = segs[1:]
+		j.stats.Removed++
+	}
+
+	j.stats.Segments = len(segs)
+	j.stats.Bytes = total
+	j.stats.LastRun = now
+	if len(errs) > 0 {
+		return fmt.Errorf("journal: remove segment: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Start runs the janitor every interval in a background goroutine until
+// the returned stop function is called. Run failures are retried at the
+// next interval.
+func (j *Janitor) Start(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				j.Run()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// Stats returns the state of the segments after the last run.
+func (j *Janitor) Stats() JanitorStats {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stats
+}
@@ -0,0 +1,125 @@
+// Package journal persists error events to disk as newline delimited JSON
+// segments, optionally compressed, which sneterr.Decoder can read back.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// DefaultMaxSegmentBytes is the segment size used when
+// Options.MaxSegmentBytes is not set.
+const DefaultMaxSegmentBytes = 64 << 20
+
+// Options configures a Journal or an Archive.
+type Options struct {
+	// Compresses the segments. They are written uncompressed when nil.
+	Compressor Compressor
+
+	// Number of uncompressed bytes after which a Journal seals its segment
+	// and starts a new one. DefaultMaxSegmentBytes is used when zero.
+	MaxSegmentBytes int64
+}
+
+// A Journal appends error events to segment files in a directory.
+//
+// Each segment is sealed with a SHA-256 checksum file once it reaches
+// Options.MaxSegmentBytes, when Rotate is called or when the Journal is
+// closed. A Journal is safe for concurrent use.
+type Journal struct {
+	dir  string
+	opts Options
+
+	mu  sync.Mutex
+	seq uint64
+	seg *segmentWriter
+}
+
+// Open returns a Journal writing segments to dir, creating it if needed.
+// Segments left by a previous Journal are kept; new events go to a new
+// segment.
+func Open(dir string, opts Options) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("journal: create directory: %w", err)
+	}
+	seq, err := nextSeq(dir)
+	if err != nil {
+		return nil, fmt.Errorf("journal: list directory: %w", err)
+	}
+
+	opts.Compressor = compressor(opts.Compressor)
+	if opts.MaxSegmentBytes <= 0 {
+		opts.MaxSegmentBytes = DefaultMaxSegmentBytes
+	}
+	return &Journal{dir: dir, opts: opts, seq: seq}, nil
+}
+
+// Write appends the JSON encoding of err to the journal.
+func (j *Journal) Write(err sneterr.Error) error {
+	return writeError(j, err)
+}
+
+// WriteEvent appends an already encoded event line to the journal.
+func (j *Journal) WriteEvent(line []byte) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.seg == nil {
+		seg, err := createSegment(j.dir, j.seq, j.opts.Compressor)
+		if err != nil {
+			return err
+		}
+		j.seg = seg
+		j.seq++
+	}
+	if err := j.seg.write(line); err != nil {
+		return err
+	}
+	if j.seg.size >= j.opts.MaxSegmentBytes {
+		return j.rotate()
+	}
+	return nil
+}
+
+// writeError writes the JSON encoding of err to sink.
+func writeError(sink Sink, err sneterr.Error) error {
+	line, mErr := json.Marshal(err)
+	if mErr != nil {
+		return fmt.Errorf("journal: encode event: %w", mErr)
+	}
+	return sink.WriteEvent(append(line, '\n'))
+}
+
+// Hook returns a sneterr.Hook writing every created error to the journal.
+// Write failures are dropped.
+func (j *Journal) Hook() sneterr.Hook {
+	return func(err sneterr.Error) {
+		j.Write(err)
+	}
+}
+
+// Rotate seals the current segment, if any. The next event starts a new
+// segment.
+func (j *Journal) Rotate() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.rotate()
+}
+
+func (j *Journal) rotate() error {
+	if j.seg == nil {
+		return nil
+	}
+	seg := j.seg
+	j.seg = nil
+	return seg.seal()
+}
+
+// Close seals the current segment.
+func (j *Journal) Close() error {
+	return j.Rotate()
+}
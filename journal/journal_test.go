@@ -0,0 +1,114 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// readSegment returns the errors of the sealed segment at path.
+func readSegment(t *testing.T, path string, c Compressor) []sneterr.Error {
+	t.Helper()
+	r, err := OpenSegment(path, c)
+	if err != nil {
+		t.Fatalf("OpenSegment(%s) error = %v", path, err)
+	}
+	defer r.Close()
+
+	d := sneterr.NewDecoder(r)
+	defer d.Close()
+	var errs []sneterr.Error
+	for {
+		e, err := d.Next(context.Background())
+		if err == io.EOF {
+			return errs
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		errs = append(errs, e)
+	}
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		compressor Compressor
+	}{
+		{name: "plain"},
+		{name: "gzip", compressor: Gzip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			j, err := Open(dir, Options{Compressor: tt.compressor})
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+
+			written := [][]sneterr.Error{
+				{
+					sneterr.New("OrderNotFound", "order was not found", sneterr.WithField("order_id", "o-7")),
+					sneterr.New("PaymentDeclined", "card was declined"),
+				},
+				{sneterr.New("StockExhausted", "item is out of stock", sneterr.WithField("sku", "A1"))},
+			}
+			for i, errs := range written {
+				for _, e := range errs {
+					if err := j.Write(e); err != nil {
+						t.Fatalf("Write() error = %v", err)
+					}
+				}
+				if i == 0 {
+					if err := j.Rotate(); err != nil {
+						t.Fatalf("Rotate() error = %v", err)
+					}
+				}
+			}
+			if err := j.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			paths, err := Segments(dir)
+			if err != nil {
+				t.Fatalf("Segments() error = %v", err)
+			}
+			if len(paths) != len(written) {
+				t.Fatalf("Segments() = %v, want %d segments", paths, len(written))
+			}
+			for i, path := range paths {
+				got := readSegment(t, path, tt.compressor)
+				if len(got) != len(written[i]) {
+					t.Fatalf("segment %s has %d errors, want %d", path, len(got), len(written[i]))
+				}
+				for k, e := range got {
+					want := written[i][k]
+					if e.Code() != want.Code() || e.Message() != want.Message() {
+						t.Errorf("error %d of %s = %s: %s, want %s: %s", k, path, e.Code(), e.Message(), want.Code(), want.Message())
+					}
+					for key, v := range sneterr.FieldsOf(want) {
+						if sneterr.FieldsOf(e)[key] != v {
+							t.Errorf("field %s of %s = %v, want %v", key, e.Code(), sneterr.FieldsOf(e)[key], v)
+						}
+					}
+				}
+			}
+
+			data, err := os.ReadFile(paths[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			data[len(data)/2] ^= 0xff
+			if err := os.WriteFile(paths[0], data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenSegment(paths[0], tt.compressor); !errors.Is(err, ErrChecksum) {
+				t.Errorf("OpenSegment() of a tampered segment error = %v, want ErrChecksum", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package journal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrChecksum is returned by OpenSegment when a segment does not match its
+// checksum.
+//
+// The package reports failures with plain errors rather than sneterr
+// errors, since creating those from within a hook would recurse into it.
+var ErrChecksum = errors.New("journal: segment does not match its checksum")
+
+const (
+	segmentPrefix  = "segment-"
+	segmentExt     = ".ndjson"
+	checksumSuffix = ".sha256"
+)
+
+// segmentName returns the file name of segment seq compressed with c.
+func segmentName(seq uint64, c Compressor) string {
+	return fmt.Sprintf("%s%012d%s%s", segmentPrefix, seq, segmentExt, c.Extension())
+}
+
+// segmentSeq returns the sequence number of the segment file name.
+func segmentSeq(name string) (uint64, bool) {
+	if !strings.HasPrefix(name, segmentPrefix) || strings.HasSuffix(name, checksumSuffix) {
+		return 0, false
+	}
+	var seq uint64
+	if _, err := fmt.Sscanf(name[len(segmentPrefix):], "%d", &seq); err != nil {
+		return 0, false
+	}
+	return seq, true
+}
+
+// Segments returns the paths of the sealed segments in dir, oldest first.
+// A segment is sealed once its checksum file is written.
+func Segments(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type segment struct {
+		seq  uint64
+		path string
+	}
+	var segs []segment
+	for _, e := range entries {
+		seq, ok := segmentSeq(e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(path + checksumSuffix); err != nil {
+			continue
+		}
+		segs = append(segs, segment{seq, path})
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i].seq < segs[j].seq })
+
+	paths := make([]string, len(segs))
+	for i, s := range segs {
+		paths[i] = s.path
+	}
+	return paths, nil
+}
+
+// OpenSegment verifies the sealed segment at path against its checksum and
+// returns a reader of its decompressed events, ready to be given to
+// sneterr.NewDecoder. c must be the Compressor the segment was written
+// with, or nil if it is not compressed.
+func OpenSegment(path string, c Compressor) (io.ReadCloser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("journal: read segment: %w", err)
+	}
+	sum, err := os.ReadFile(path + checksumSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("journal: read segment checksum: %w", err)
+	}
+
+	want, _, _ := strings.Cut(string(sum), " ")
+	got := sha256.Sum256(data)
+	if want != hex.EncodeToString(got[:]) {
+		return nil, fmt.Errorf("%w: %s", ErrChecksum, path)
+	}
+
+	r, err := compressor(c).NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("journal: decompress segment: %w", err)
+	}
+	return r, nil
+}
+
+// segmentWriter writes the events of one segment, checksumming the bytes
+// stored on disk.
+type segmentWriter struct {
+	path string
+	file *os.File
+	sum  hash.Hash
+	w    io.WriteCloser
+
+	// Number of uncompressed bytes written.
+	size int64
+}
+
+// createSegment creates the segment file seq in dir.
+func createSegment(dir string, seq uint64, c Compressor) (*segmentWriter, error) {
+	path := filepath.Join(dir, segmentName(seq, c))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("journal: create segment: %w", err)
+	}
+
+	s := &segmentWriter{path: path, file: f, sum: sha256.New()}
+	s.w, err = c.NewWriter(io.MultiWriter(f, s.sum))
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("journal: start segment compression: %w", err)
+	}
+	return s, nil
+}
+
+// write appends one event line to the segment.
+func (s *segmentWriter) write(line []byte) error {
+	n, err := s.w.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("journal: write segment: %w", err)
+	}
+	return nil
+}
+
+// seal flushes and closes the segment, then writes its checksum file.
+func (s *segmentWriter) seal() error {
+	err := errors.Join(s.w.Close(), s.file.Sync(), s.file.Close())
+	if err != nil {
+		return fmt.Errorf("journal: close segment: %w", err)
+	}
+
+	line := hex.EncodeToString(s.sum.Sum(nil)) + "  " + filepath.Base(s.path) + "\n"
+	if err := os.WriteFile(s.path+checksumSuffix, []byte(line), 0o644); err != nil {
+		return fmt.Errorf("journal: write segment checksum: %w", err)
+	}
+	return nil
+}
+
+// nextSeq returns the sequence number following the last segment in dir.
+func nextSeq(dir string) (uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+	var next uint64
+	for _, e := range entries {
+		if seq, ok := segmentSeq(e.Name()); ok && seq >= next {
+			next = seq + 1
+		}
+	}
+	return next, nil
+}
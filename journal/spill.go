@@ -0,0 +1,302 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// A Sink receives encoded error events, one JSON line per call. Journal and
+// Archive are Sinks.
+type Sink interface {
+	WriteEvent(line []byte) error
+}
+
+// DefaultRetryInterval is the delay between delivery attempts used when
+// SpillOptions.RetryInterval is not set.
+const DefaultRetryInterval = time.Second
+
+// SpillOptions configures a Spill.
+type SpillOptions struct {
+	// Delay before retrying a failed delivery. DefaultRetryInterval is
+	// used when zero.
+	RetryInterval time.Duration
+}
+
+// SpillStats reports the backlog of a Spill.
+type SpillStats struct {
+	// Number of events waiting to be delivered.
+	Pending int64
+
+	// Size in bytes of the on-disk queue.
+	Bytes int64
+
+	// Age of the oldest event waiting to be delivered, zero when the queue
+	// is empty.
+	ReplayLag time.Duration
+}
+
+const (
+	spillQueue  = "spill.queue"
+	spillOffset = "spill.offset"
+)
+
+// A Spill delivers events to a critical Sink, such as an audit or
+// compliance store, at least once.
+//
+// Every event is first appended to an on-disk queue, then delivered in
+// order by a background goroutine which retries failed deliveries until
+// they succeed. Events still queued when the process stops are replayed by
+// the next Spill opened on the same directory, so an event may be
+// delivered more than once but is not lost.
+type Spill struct {
+	sink Sink
+	opts SpillOptions
+
+	queue   *os.File
+	reader  *os.File
+	offPath string
+
+	mu      sync.Mutex
+	size    int64
+	offset  int64
+	pending int64
+	oldest  time.Time
+
+	wake chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSpill returns a Spill delivering to sink with its queue in dir,
+// creating it if needed, and starts replaying the events left queued by a
+// previous Spill.
+func NewSpill(sink Sink, dir string, opts SpillOptions) (*Spill, error) {
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultRetryInterval
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("journal: create spill directory: %w", err)
+	}
+
+	path := filepath.Join(dir, spillQueue)
+	queue, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("journal: open spill queue: %w", err)
+	}
+	reader, err := os.Open(path)
+	if err != nil {
+		queue.Close()
+		return nil, fmt.Errorf("journal: open spill queue: %w", err)
+	}
+
+	s := &Spill{
+		sink:    sink,
+		opts:    opts,
+		queue:   queue,
+		reader:  reader,
+		offPath: filepath.Join(dir, spillOffset),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if err := s.recover(); err != nil {
+		queue.Close()
+		reader.Close()
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.deliver()
+	return s, nil
+}
+
+// recover loads the delivery offset and counts the queued events.
+func (s *Spill) recover() error {
+	info, err := s.queue.Stat()
+	if err != nil {
+		return fmt.Errorf("journal: stat spill queue: %w", err)
+	}
+	s.size = info.Size()
+
+	if b, err := os.ReadFile(s.offPath); err == nil {
+		s.offset, _ = strconv.ParseInt(string(bytes.TrimSpace(b)), 10, 64)
+	}
+	if s.offset < 0 || s.offset > s.size {
+		s.offset = 0
+	}
+
+	br := bufio.NewReader(io.NewSectionReader(s.reader, s.offset, s.size-s.offset))
+	for {
+		rec, err := br.ReadBytes('\n')
+		if len(rec) > 0 && rec[len(rec)-1] == '\n' {
+			if s.pending == 0 {
+				s.oldest, _ = parseRecord(rec)
+			}
+			s.pending++
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// WriteEvent queues line for delivery. It returns once the event is
+// written to the on-disk queue.
+func (s *Spill) WriteEvent(line []byte) error {
+	now := time.Now()
+	rec := make([]byte, 0, len(line)+21)
+	rec = strconv.AppendInt(rec, now.UnixNano(), 10)
+	rec = append(rec, ' ')
+	rec = append(rec, bytes.TrimRight(line, "\n")...)
+	rec = append(rec, '\n')
+
+	s.mu.Lock()
+	n, err := s.queue.Write(rec)
+	s.size += int64(n)
+	if err == nil {
+		if s.pending == 0 {
+			s.oldest = now
+		}
+		s.pending++
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("journal: write spill queue: %w", err)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Write queues the JSON encoding of err for delivery.
+func (s *Spill) Write(err sneterr.Error) error {
+	return writeError(s, err)
+}
+
+// Hook returns a sneterr.Hook queuing every created error for delivery.
+// Queue write failures are dropped.
+func (s *Spill) Hook() sneterr.Hook {
+	return func(err sneterr.Error) {
+		s.Write(err)
+	}
+}
+
+// Stats returns the current backlog of the Spill.
+func (s *Spill) Stats() SpillStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := SpillStats{Pending: s.pending, Bytes: s.size}
+	if s.pending > 0 && !s.oldest.IsZero() {
+		st.ReplayLag = time.Since(s.oldest)
+	}
+	return st
+}
+
+// Close stops delivering events. Events still queued are delivered by the
+// next Spill opened on the same directory.
+func (s *Spill) Close() error {
+	close(s.done)
+	s.wg.Wait()
+	return errors.Join(s.queue.Close(), s.reader.Close())
+}
+
+// deliver sends the queued events to the sink in order until the Spill is
+// closed.
+func (s *Spill) deliver() {
+	defer s.wg.Done()
+
+	for {
+		rec, next, ok := s.head()
+		if !ok {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		_, line := parseRecord(rec)
+		if err := s.sink.WriteEvent(line); err != nil {
+			select {
+			case <-time.After(s.opts.RetryInterval):
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		s.ack(next)
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
+
+// head returns the oldest queued record and the offset following it.
+func (s *Spill) head() ([]byte, int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headLocked()
+}
+
+// ack records the delivery of the records before next. The queue is
+// truncated once everything was delivered.
+func (s *Spill) ack(next int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.offset = next
+	s.pending--
+	s.oldest = time.Time{}
+	if s.offset >= s.size {
+		if err := s.queue.Truncate(0); err == nil {
+			s.offset, s.size = 0, 0
+		}
+	} else if rec, _, ok := s.headLocked(); ok {
+		s.oldest, _ = parseRecord(rec)
+	}
+	os.WriteFile(s.offPath, strconv.AppendInt(nil, s.offset, 10), 0o644)
+}
+
+// headLocked is head for callers holding s.mu. A partial record, still
+// being written, is not returned.
+func (s *Spill) headLocked() ([]byte, int64, bool) {
+	if s.offset >= s.size {
+		return nil, 0, false
+	}
+	br := bufio.NewReader(io.NewSectionReader(s.reader, s.offset, s.size-s.offset))
+	rec, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, 0, false
+	}
+	return rec, s.offset + int64(len(rec)), true
+}
+
+// parseRecord splits a queue record into its enqueue time and event line.
+func parseRecord(rec []byte) (time.Time, []byte) {
+	ts, line, ok := bytes.Cut(rec, []byte(" "))
+	if !ok {
+		return time.Time{}, rec
+	}
+	ns, err := strconv.ParseInt(string(ts), 10, 64)
+	if err != nil {
+		return time.Time{}, rec
+	}
+	return time.Unix(0, ns), line
+}
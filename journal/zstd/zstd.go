@@ -0,0 +1,37 @@
+// Package zstd provides a journal.Compressor using Zstandard.
+package zstd
+
+import (
+	"io"
+
+	kzstd "github.com/klauspost/compress/zstd"
+	"github.com/servicenetjp/sneterr/journal"
+)
+
+// Compressor compresses segments with Zstandard at the default level.
+var Compressor journal.Compressor = Level(kzstd.SpeedDefault)
+
+// Level returns a journal.Compressor using Zstandard at the given level.
+func Level(level kzstd.EncoderLevel) journal.Compressor {
+	return compressor{level: level}
+}
+
+type compressor struct {
+	level kzstd.EncoderLevel
+}
+
+func (compressor) Extension() string {
+	return ".zst"
+}
+
+func (c compressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return kzstd.NewWriter(w, kzstd.WithEncoderLevel(c.level))
+}
+
+func (compressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := kzstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
@@ -0,0 +1,232 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	jsonFingerprint atomic.Bool
+	jsonDedupWindow atomic.Int64
+)
+
+// SetJSONFingerprint sets whether errors encoded as JSON include their
+// Fingerprint in a "fingerprint" member. It is disabled by default.
+func SetJSONFingerprint(enabled bool) {
+	jsonFingerprint.Store(enabled)
+}
+
+// SetJSONDedupKey makes errors encoded as JSON include their DedupKey for
+// window in a "dedupKey" member, for the trackers and webhooks receiving
+// them. Their fingerprint is included too, so that the processes decoding
+// them derive the same key. A window of zero, the default, leaves the
+// member out.
+func SetJSONDedupKey(window time.Duration) {
+	jsonDedupWindow.Store(int64(window))
+}
+
+// jsonError is the JSON representation of an error.
+type jsonError struct {
+	Code        string       `json:"code,omitempty"`
+	Message     string       `json:"message"`
+	Fields      Fields       `json:"fields,omitempty"`
+	File        string       `json:"file,omitempty"`
+	Line        int          `json:"line,omitempty"`
+	Time        time.Time    `json:"time,omitzero"`
+	Duration    string       `json:"duration,omitempty"`
+	Fingerprint string       `json:"fingerprint,omitempty"`
+	DedupKey    string       `json:"dedupKey,omitempty"`
+	Annotated   bool         `json:"annotated,omitempty"`
+	MarshalErr  string       `json:"marshalError,omitempty"`
+	Errors      []*jsonError `json:"errors,omitempty"`
+	Saga        *jsonSaga    `json:"saga,omitempty"`
+	Cause       *jsonError   `json:"cause,omitempty"`
+}
+
+// toJSON returns the JSON representation of err and its cause chain.
+// Errors which are not an Error are represented by their message only.
+func toJSON(err error) *jsonError {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *baseError:
+		j := &jsonError{
+			Code:      e.code,
+			Message:   e.Message(),
+			Fields:    e.fields,
+			File:      e.file,
+			Line:      e.line,
+			Time:      e.time,
+			Annotated: e.annotated,
+			Cause:     toJSON(e.err),
+		}
+		if e.duration != 0 {
+			j.Duration = e.duration.String()
+		}
+		return j
+	case *requestError:
+		return toJSON(e.sneterror)
+	case *sagaError:
+		j := toJSON(e.sneterror)
+		j.Saga = e.toJSON()
+		return j
+	case MultiError:
+		j := &jsonError{Code: e.Code(), Message: e.Message()}
+		for _, err := range OrderedErrors(e) {
+			j.Errors = append(j.Errors, toJSON(err))
+		}
+		return j
+	case Error:
+		return &jsonError{Code: e.Code(), Message: e.Message(), Cause: toJSON(e.OrigErr())}
+	}
+	return &jsonError{Message: err.Error()}
+}
+
+// marshalError encodes err as JSON, adding its fingerprint when enabled by
+// SetJSONFingerprint.
+//
+// If the encoding fails, because of field values which cannot be encoded
+// such as channels or cyclic data, the fields at fault are replaced with a
+// placeholder naming their type, and the failure is noted in a
+// "marshalError" member. Should that fail too, only the code, message and
+// note are encoded, so errors always have an encoding.
+func marshalError(err error) ([]byte, error) {
+	j := toJSON(err)
+	window := time.Duration(jsonDedupWindow.Load())
+	if jsonFingerprint.Load() || window > 0 {
+		j.Fingerprint = Fingerprint(err)
+	}
+	if window > 0 {
+		j.DedupKey = DedupKey(err, window)
+	}
+
+	b, mErr := json.Marshal(j)
+	if mErr == nil {
+		return b, nil
+	}
+	j.sanitize()
+	j.MarshalErr = mErr.Error()
+	if b, err := json.Marshal(j); err == nil {
+		return b, nil
+	}
+	return json.Marshal(&jsonError{Code: j.Code, Message: j.Message, MarshalErr: mErr.Error()})
+}
+
+// sanitize replaces the fields of j and of the errors it holds which
+// cannot be encoded as JSON with a placeholder naming their type.
+func (j *jsonError) sanitize() {
+	if j == nil {
+		return
+	}
+	j.Fields = sanitizeFields(j.Fields)
+	for _, e := range j.Errors {
+		e.sanitize()
+	}
+	if j.Saga != nil {
+		for _, c := range j.Saga.Compensations {
+			c.Error.sanitize()
+		}
+	}
+	j.Cause.sanitize()
+}
+
+// sanitizeFields returns a copy of fields where the values which cannot be
+// encoded as JSON are replaced with a placeholder naming their type.
+// fields itself is left unchanged, as it belongs to an error.
+func sanitizeFields(fields Fields) Fields {
+	var out Fields
+	for k, v := range fields {
+		if _, err := json.Marshal(v); err == nil {
+			continue
+		}
+		if out == nil {
+			out = fields.clone()
+		}
+		out[k] = fmt.Sprintf("<unserializable %T>", v)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// MarshalJSON encodes the error, its fields and its cause chain as JSON.
+func (b baseError) MarshalJSON() ([]byte, error) {
+	if b.cache != nil {
+		return b.cache.marshal(b)
+	}
+	return b.marshal()
+}
+
+// marshal encodes the error as JSON. It takes a copy of b, which escapes,
+// for MarshalJSON not to allocate one when the encoding is cached.
+func (b baseError) marshal() ([]byte, error) {
+	return marshalError(&b)
+}
+
+// MarshalJSON encodes the error of the failed request as JSON.
+func (r requestError) MarshalJSON() ([]byte, error) {
+	return marshalError(&r)
+}
+
+// MarshalJSON encodes the error of the failed saga and its compensations
+// as JSON.
+func (s sagaError) MarshalJSON() ([]byte, error) {
+	return marshalError(&s)
+}
+
+// MarshalJSON encodes the error and the grouped errors as JSON.
+func (m multiError) MarshalJSON() ([]byte, error) {
+	return marshalError(&m)
+}
+
+// fromJSON rebuilds the error described by j, frozen. The location
+// recorded in j is kept, no stack is captured and hooks are not notified.
+func fromJSON(j *jsonError) error {
+	if j == nil {
+		return nil
+	}
+	if len(j.Errors) > 0 {
+		errs := make([]error, 0, len(j.Errors))
+		for _, e := range j.Errors {
+			errs = append(errs, fromJSON(e))
+		}
+		return NewMultiError(j.Code, j.Message, errs)
+	}
+	if j.Code == "" && j.Cause == nil && len(j.Fields) == 0 {
+		return errors.New(j.Message)
+	}
+
+	b := newBaseError(j.Code, j.Message, fromJSON(j.Cause), j.File, j.Line)
+	b.fields = j.Fields
+	b.time = j.Time
+	b.duration, _ = time.ParseDuration(j.Duration)
+	b.fingerprint = j.Fingerprint
+	b.frozen = true
+	b.annotated = j.Annotated
+	if j.Saga != nil {
+		return sagaFromJSON(b, j.Saga)
+	}
+	return b
+}
+
+// UnmarshalError rebuilds an error from its JSON encoding, as produced by
+// the MarshalJSON method of the errors of this package, typically received
+// from another process. The location, creation time and fingerprint
+// recorded in data are kept. Hooks are not notified. The errors rebuilt
+// are frozen, see Frozen.
+func UnmarshalError(data []byte) (Error, error) {
+	var j jsonError
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	if j.Code == "" {
+		return nil, errors.New("sneterr: encoded error has no code")
+	}
+	return fromJSON(&j).(Error), nil
+}
@@ -0,0 +1,105 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cycle is a value referencing itself, which encoding/json rejects.
+type cycle struct {
+	Next *cycle
+}
+
+// flakyMarshaler only encodes on its second call, the one checking it in
+// sanitizeFields, so that the encoding of the sanitized error fails too.
+type flakyMarshaler struct {
+	calls *int
+}
+
+func (f flakyMarshaler) MarshalJSON() ([]byte, error) {
+	*f.calls++
+	if *f.calls != 2 {
+		return nil, errors.New("flaky")
+	}
+	return []byte(`"ok"`), nil
+}
+
+func TestMarshalErrorFallback(t *testing.T) {
+	c := &cycle{}
+	c.Next = c
+
+	tests := []struct {
+		name        string
+		value       interface{}
+		placeholder string
+		minimal     bool
+	}{
+		{name: "channel", value: make(chan int), placeholder: "<unserializable chan int>"},
+		{name: "func", value: func() {}, placeholder: "<unserializable func()>"},
+		{name: "cycle", value: c, placeholder: "<unserializable *sneterr.cycle>"},
+		{name: "failed fallback", value: flakyMarshaler{calls: new(int)}, minimal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New("Broken", "cannot encode", WithField("bad", tt.value), WithField("good", 1))
+			b := err.(*baseError)
+
+			data, mErr := json.Marshal(err)
+			if mErr != nil {
+				t.Fatalf("Marshal() error = %v", mErr)
+			}
+			var got map[string]interface{}
+			if uErr := json.Unmarshal(data, &got); uErr != nil {
+				t.Fatalf("encoding %s is not valid JSON: %v", data, uErr)
+			}
+
+			if got["code"] != "Broken" || got["message"] != "cannot encode" {
+				t.Errorf("code and message = %v, %v, want Broken, cannot encode", got["code"], got["message"])
+			}
+			if note, _ := got["marshalError"].(string); note == "" {
+				t.Errorf("encoding %s has no marshalError member", data)
+			}
+			fields, _ := got["fields"].(map[string]interface{})
+			if tt.minimal {
+				if fields != nil {
+					t.Errorf("minimal encoding %s has fields", data)
+				}
+			} else {
+				if fields["bad"] != tt.placeholder {
+					t.Errorf("field bad = %v, want %q", fields["bad"], tt.placeholder)
+				}
+				if fields["good"] != 1.0 {
+					t.Errorf("field good = %v, want 1", fields["good"])
+				}
+			}
+
+			if _, ok := b.fields["bad"].(string); ok {
+				t.Errorf("field bad of the error was replaced with %q", b.fields["bad"])
+			}
+			if len(b.fields) != 2 {
+				t.Errorf("error has %d fields, want 2", len(b.fields))
+			}
+		})
+	}
+}
+
+func TestMarshalErrorFallbackNested(t *testing.T) {
+	cause := New("Inner", "inner", WithField("ch", make(chan int)))
+	err := Join(Wrap(cause, "Outer", "outer"), New("Other", "other"))
+
+	data, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Marshal() error = %v", mErr)
+	}
+	if !json.Valid(data) {
+		t.Fatalf("encoding %s is not valid JSON", data)
+	}
+	if s := string(data); !strings.Contains(s, "unserializable chan int") || !strings.Contains(s, `"marshalError"`) {
+		t.Errorf("encoding %s lacks the placeholder or the marshalError member", s)
+	}
+	if _, ok := cause.(*baseError).fields["ch"].(chan int); !ok {
+		t.Errorf("field ch of the cause was replaced")
+	}
+}
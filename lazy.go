@@ -0,0 +1,60 @@
+package sneterr
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A lazyMessage is a message formatted on first use.
+type lazyMessage struct {
+	once   sync.Once
+	format string
+	args   []interface{}
+	text   string
+}
+
+// String returns the formatted message.
+func (l *lazyMessage) String() string {
+	l.once.Do(func() {
+		l.text = fmt.Sprintf(l.format, l.args...)
+		l.args = nil
+	})
+	return l.text
+}
+
+// Newf returns an Error with code and a message formatted from format and
+// args by fmt.Sprintf, configured by opts, which may be nil. The message is
+// only formatted when it is first used, by Error, Message or MarshalJSON
+// for instance, so errors created on hot paths and handled without being
+// rendered do not pay for it:
+//
+//	err := sneterr.Newf("OrderNotFound", []sneterr.Option{sneterr.WithStatus(http.StatusNotFound)},
+//		"order %d was not found", id)
+//
+// As formatting is delayed, args must not be modified after the call.
+func Newf(code string, opts []Option, format string, args ...interface{}) Error {
+	b := newBaseError(code, "", nil, "", 0)
+	b.lazy = &lazyMessage{format: format, args: args}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// Wrapf is like Wrap with a message formatted like Newf does. If err is nil
+// Wrapf returns nil.
+func Wrapf(err error, code string, opts []Option, format string, args ...interface{}) Error {
+	if err == nil {
+		return nil
+	}
+	b := newBaseError(code, "", err, "", 0)
+	b.lazy = &lazyMessage{format: format, args: args}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// Messagef sets the message of the error, formatted from format and args
+// when first used like Newf does.
+func (bld *Builder) Messagef(format string, args ...interface{}) *Builder {
+	bld.b.message = ""
+	bld.b.lazy = &lazyMessage{format: format, args: args}
+	return bld
+}
@@ -0,0 +1,30 @@
+package sneterr
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestNewfOptions(t *testing.T) {
+	opts := []Option{WithStatus(http.StatusNotFound), WithField("order_id", 7)}
+	cause := errors.New("no rows")
+
+	for name, err := range map[string]Error{
+		"Newf":  Newf("OrderNotFound", opts, "order %d was not found", 7),
+		"Wrapf": Wrapf(cause, "OrderNotFound", opts, "order %d was not found", 7),
+	} {
+		if got := err.Message(); got != "order 7 was not found" {
+			t.Errorf("%s: Message() = %q, want %q", name, got, "order 7 was not found")
+		}
+		if got := HTTPStatus(err); got != http.StatusNotFound {
+			t.Errorf("%s: HTTPStatus() = %d, want %d", name, got, http.StatusNotFound)
+		}
+		if got, _ := Field[int](err, "order_id"); got != 7 {
+			t.Errorf("%s: field order_id = %v, want 7", name, got)
+		}
+	}
+	if err := Wrapf(nil, "OrderNotFound", nil, "order %d was not found", 7); err != nil {
+		t.Errorf("Wrapf(nil) = %v, want nil", err)
+	}
+}
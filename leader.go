@@ -0,0 +1,57 @@
+package sneterr
+
+import "net/http"
+
+// ErrCodeNotLeader is the code of the errors returned by NewNotLeader.
+const ErrCodeNotLeader = "NotLeader"
+
+// FieldLeader is the field key of the address of the current leader of
+// the errors returned by NewNotLeader.
+const FieldLeader = "leader"
+
+func init() {
+	Register(CodeInfo{
+		Code:      ErrCodeNotLeader,
+		Category:  CategoryUnavailable,
+		Severity:  SeverityInfo,
+		Retryable: true,
+		Status:    http.StatusServiceUnavailable,
+		Fields:    Schema{FieldLeader: FieldString},
+	})
+}
+
+// NewNotLeader returns an Error with ErrCodeNotLeader reporting that a
+// node of a cluster cannot serve a request because it is not the leader.
+// leader is the address of the current leader, such as "10.0.0.2:8443" or
+// "https://node2.example.com", attached as the FieldLeader field, or ""
+// while an election is in progress.
+//
+// The error is rendered with http.StatusTemporaryRedirect when the leader
+// is known, sneterrhttp then redirecting the client to it, and with
+// http.StatusServiceUnavailable otherwise.
+func NewNotLeader(leader string, opts ...Option) Error {
+	b := newBaseError(ErrCodeNotLeader, "this node is not the leader", nil, "", 0)
+	if leader != "" {
+		b.message = "this node is not the leader, the leader is " + leader
+		b.status = http.StatusTemporaryRedirect
+		b.fields = Fields{FieldLeader: leader}
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// Leader returns the address of the current leader reported by the first
+// error with ErrCodeNotLeader in the chain of err. It reports false if
+// there is no such error or the leader was unknown.
+func Leader(err error) (string, bool) {
+	var leader string
+	walk(err, func(e error) bool {
+		se, ok := e.(Error)
+		if !ok || se.Code() != ErrCodeNotLeader {
+			return true
+		}
+		leader, _ = matchedFields(se)[FieldLeader].(string)
+		return false
+	})
+	return leader, leader != ""
+}
@@ -0,0 +1,59 @@
+package sneterr
+
+import (
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+)
+
+var logLanguage atomic.Value
+
+// SetLogLanguage sets the language of the messages of errors created by
+// NewT when they are logged, so logs read the same whatever language the
+// default templates are written in. The message of the error is logged
+// when the template of its code was not translated to lang. An empty lang
+// restores the default of logging the message of the error.
+func SetLogLanguage(lang string) {
+	logLanguage.Store(lang)
+}
+
+// logMessage returns the message of b as it is logged.
+func (b baseError) logMessage() string {
+	lang, _ := logLanguage.Load().(string)
+	if lang == "" || !b.templated {
+		return b.Message()
+	}
+	if msg, ok := b.localize(lang); ok {
+		return msg
+	}
+	return b.Message()
+}
+
+// LogValue returns the error as a slog group of its code, message, fields,
+// location and cause.
+func (b baseError) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs,
+		slog.String("code", b.code),
+		slog.String("message", b.logMessage()),
+	)
+	if len(b.fields) > 0 {
+		attrs = append(attrs, slog.Any("fields", map[string]interface{}(b.fields)))
+	}
+	if b.file != "" {
+		attrs = append(attrs, slog.String("location", b.file+":"+strconv.Itoa(b.line)))
+	}
+	if b.err != nil {
+		attrs = append(attrs, slog.Any("cause", logCause(b.err)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// logCause returns the value logged for the cause of an error, keeping
+// the structure of causes which are slog.LogValuers.
+func logCause(err error) interface{} {
+	if _, ok := err.(slog.LogValuer); ok {
+		return err
+	}
+	return err.Error()
+}
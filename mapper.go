@@ -0,0 +1,70 @@
+package sneterr
+
+import "errors"
+
+// A Mapper translates the codes of errors crossing a boundary between
+// layers, so a layer can expose its own vocabulary:
+//
+//	var toDomain = sneterr.NewMapper().
+//		Map("RepoNotFound", "OrderNotFound").
+//		Default("Internal")
+//
+//	return toDomain.Apply(err)
+//
+// A Mapper must not be modified once it is in use. Apply is safe for
+// concurrent use.
+type Mapper struct {
+	rules map[string]string
+	def   string
+}
+
+// NewMapper returns an empty Mapper, which leaves every error unchanged.
+func NewMapper() *Mapper {
+	return &Mapper{rules: map[string]string{}}
+}
+
+// Map makes the mapper translate errors with code from into code to. It
+// returns m to allow chaining.
+func (m *Mapper) Map(from, to string) *Mapper {
+	m.rules[from] = to
+	return m
+}
+
+// Default makes the mapper translate errors matching no rule into code to,
+// including errors which do not satisfy the Error interface. It returns m
+// to allow chaining.
+func (m *Mapper) Default(to string) *Mapper {
+	m.def = to
+	return m
+}
+
+// Apply returns err translated by the mapper rules. The translated error
+// keeps the message of err and wraps it as its original error, so the
+// original code remains inspectable with errors.As.
+//
+// Errors matching no rule are returned unchanged when the mapper has no
+// default; those which do not satisfy the Error interface are then given
+// ErrCodeUnknown. If err is nil Apply returns nil.
+func (m *Mapper) Apply(err error) Error {
+	if err == nil {
+		return nil
+	}
+
+	code, message := ErrCodeUnknown, err.Error()
+	var e Error
+	if errors.As(err, &e) {
+		code, message = e.Code(), e.Message()
+	}
+
+	to, ok := m.rules[code]
+	if !ok {
+		to = m.def
+	}
+	if to == "" {
+		if e, ok := err.(Error); ok {
+			return e
+		}
+		return derive(err)
+	}
+	return newError(2, to, message, err)
+}
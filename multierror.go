@@ -0,0 +1,113 @@
+package sneterr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ErrCodeMultiple is the code of a MultiError built without a more
+// specific classification.
+const ErrCodeMultiple = "MultipleErrors"
+
+// A MultiError groups several errors under a single code and message.
+type MultiError interface {
+	Error
+
+	// Returns the grouped errors.
+	Errors() []error
+}
+
+// NewMultiError returns a MultiError for the code, message and errs.
+func NewMultiError(code, message string, errs []error) MultiError {
+	return &multiError{code: code, message: message, errs: errs}
+}
+
+// multiError is the MultiError implementation of the package.
+type multiError struct {
+	code    string
+	message string
+	errs    []error
+}
+
+// Error returns the string representation of the error, one line per
+// grouped error in the order set by SetErrorOrder.
+//
+// Satisfies the error interface.
+func (m multiError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(SprintError(m.code, m.message, "", nil))
+	for _, err := range OrderedErrors(m) {
+		sb.WriteString("\n\t")
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// String returns the string representation of the error.
+// Alias for Error to satisfy the stringer interface.
+func (m multiError) String() string {
+	return m.Error()
+}
+
+// Code returns the short phrase depicting the classification of the error.
+func (m multiError) Code() string {
+	return m.code
+}
+
+// Message returns the error details message.
+func (m multiError) Message() string {
+	return m.message
+}
+
+// OrigErr returns the first grouped error, or nil if there are none.
+func (m multiError) OrigErr() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return m.errs[0]
+}
+
+// Errors returns the grouped errors.
+func (m multiError) Errors() []error {
+	return m.errs
+}
+
+// Unwrap returns the grouped errors so errors.Is and errors.As inspect
+// each of them.
+func (m multiError) Unwrap() []error {
+	return m.errs
+}
+
+// Join returns an Error combining the non-nil errs, or nil if there are
+// none. Like errors.Join, the result implements Unwrap() []error so
+// errors.Is and errors.As inspect every combined error, and each of them
+// keeps its own code. The combined error has ErrCodeMultiple.
+func Join(errs ...error) Error {
+	var joined []error
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+		}
+	}
+	if len(joined) == 0 {
+		return nil
+	}
+	msg := strconv.Itoa(len(joined)) + " errors occurred"
+	if len(joined) == 1 {
+		msg = "1 error occurred"
+	}
+	return NewMultiError(ErrCodeMultiple, msg, joined)
+}
+
+// Split returns the errors combined in err by Join, errors.Join or any
+// error implementing Unwrap() []error. Any other non-nil error is returned
+// as the only element.
+func Split(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		return append([]error(nil), u.Unwrap()...)
+	}
+	return []error{err}
+}
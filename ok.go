@@ -0,0 +1,56 @@
+package sneterr
+
+// OK is the nil Error, returned explicitly by operations which succeeded
+// to make success stand out from failures in orchestrator code:
+//
+//	if done {
+//		return sneterr.OK
+//	}
+//
+// It compares equal to nil, also as an error.
+var OK Error
+
+// Or returns err as an Error with its code if it has one, that is an
+// Error in its chain, and err wrapped with fallbackCode and its message
+// otherwise. If err is nil Or returns OK.
+//
+//	return sneterr.Or(client.Do(req), "UpstreamFailed")
+func Or(err error, fallbackCode string) Error {
+	if err == nil {
+		return OK
+	}
+	if e, ok := err.(Error); ok {
+		return e
+	}
+	if inner, ok := As[Error](err); ok {
+		return newError(2, inner.Code(), err.Error(), err)
+	}
+	return newError(2, fallbackCode, err.Error(), err)
+}
+
+// Coalesce returns the first error of errs which has a code, that is an
+// Error in its chain. If none has, it returns the first non-nil error
+// wrapped with ErrCodeUnknown, and OK if all are nil.
+//
+//	return sneterr.Coalesce(validateErr, quotaErr, storeErr)
+func Coalesce(errs ...error) Error {
+	var first error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if e, ok := err.(Error); ok {
+			return e
+		}
+		if inner, ok := As[Error](err); ok {
+			return newError(2, inner.Code(), err.Error(), err)
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	if first == nil {
+		return OK
+	}
+	return newError(2, ErrCodeUnknown, first.Error(), first)
+}
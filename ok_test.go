@@ -0,0 +1,43 @@
+package sneterr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOrCoalesce(t *testing.T) {
+	inner := New("NotFound", "not found")
+	wrapped := fmt.Errorf("lookup: %w", inner)
+	foreign := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  func() Error
+		code string
+	}{
+		{"Or wrapped", func() Error { return Or(wrapped, "Fallback") }, "NotFound"},
+		{"Or foreign", func() Error { return Or(foreign, "Fallback") }, "Fallback"},
+		{"Coalesce wrapped", func() Error { return Coalesce(nil, foreign, wrapped) }, "NotFound"},
+		{"Coalesce foreign", func() Error { return Coalesce(nil, foreign) }, ErrCodeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.err()
+			if err.Code() != tt.code {
+				t.Errorf("Code() = %q, want %q", err.Code(), tt.code)
+			}
+			b := err.(*baseError)
+			if b.time.IsZero() || len(b.stack) == 0 {
+				t.Errorf("error has no creation time or stack")
+			}
+			if b.file != "ok_test.go" {
+				t.Errorf("location = %s:%d, want ok_test.go", b.file, b.line)
+			}
+		})
+	}
+
+	if Or(nil, "Fallback") != OK || Coalesce(nil, nil) != OK {
+		t.Errorf("Or and Coalesce of nil errors are not OK")
+	}
+}
@@ -0,0 +1,113 @@
+package sneterr
+
+// An Option sets a property of an Error created by New or With. A nil
+// Option is ignored.
+type Option func(*baseError)
+
+// WithCause sets the original error, which the Error wraps.
+func WithCause(err error) Option {
+	return func(b *baseError) {
+		b.err = err
+	}
+}
+
+// WithField attaches the field key with value. A field already attached
+// with the same key is replaced. A *url.URL or *http.Request value is
+// attached as its URL redacted, see SetURLRedactor.
+func WithField(key string, value interface{}) Option {
+	value = redactField(value)
+	return func(b *baseError) {
+		if b.fields == nil {
+			b.fields = Fields{}
+		}
+		b.fields[key] = value
+	}
+}
+
+// WithStatus sets the HTTP status of responses for the error, overriding
+// the one registered for its code.
+func WithStatus(status int) Option {
+	return func(b *baseError) {
+		b.status = status
+	}
+}
+
+// apply applies opts to b, checking the fields it ends up with in dev
+// mode.
+func (b *baseError) apply(opts []Option) {
+	if len(opts) == 0 {
+		return
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	checkFields(b.code, b.fields)
+}
+
+// With returns a copy of err with opts applied. Errors are immutable: err
+// itself is left unchanged, like with WithFields and WithDuration.
+//
+// If err does not satisfy the Error interface it is wrapped as the original
+// error of a new Error with ErrCodeUnknown. If err is nil With returns nil.
+func With(err error, opts ...Option) Error {
+	if err == nil {
+		return nil
+	}
+
+	b := derive(err)
+	b.fields = b.fields.clone()
+	b.apply(opts)
+	return b
+}
+
+// A Builder creates an Error step by step. Unlike options, its methods do
+// not allocate closures, so it suits errors created on hot paths:
+//
+//	err := sneterr.Build("Timeout", "upstream timed out").
+//		Cause(cause).
+//		Field("upstream", name).
+//		Err()
+//
+// A Builder must not be used after Err.
+type Builder struct {
+	b baseError
+}
+
+// Build returns a Builder for an error with code and message.
+func Build(code, message string) *Builder {
+	return &Builder{b: baseError{code: code, message: message}}
+}
+
+// Cause sets the original error, which the Error wraps.
+func (bld *Builder) Cause(err error) *Builder {
+	bld.b.err = err
+	return bld
+}
+
+// Field attaches the field key with value, redacted like with WithField.
+func (bld *Builder) Field(key string, value interface{}) *Builder {
+	if bld.b.fields == nil {
+		bld.b.fields = Fields{}
+	}
+	bld.b.fields[key] = redactField(value)
+	return bld
+}
+
+// Status sets the HTTP status of responses for the error.
+func (bld *Builder) Status(status int) *Builder {
+	bld.b.status = status
+	return bld
+}
+
+// Err returns the Error built, recording the caller of Err as its
+// location and notifying the registered hooks about it.
+func (bld *Builder) Err() Error {
+	b := new(baseError)
+	*b = bld.b
+	if b.fields != nil {
+		checkFields(b.code, b.fields)
+	}
+	return finishError(2, b)
+}
@@ -0,0 +1,99 @@
+package sneterr
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// An ErrorOrder is the order in which the errors grouped by a MultiError
+// are rendered and serialized.
+type ErrorOrder int32
+
+// Orders of the grouped errors.
+const (
+	// The order in which the errors were grouped, the default. Errors
+	// collected from goroutines, such as by a Group, come in a different
+	// order from one run to the next.
+	OrderInsertion ErrorOrder = iota
+
+	// The most serious errors first, see SeverityOf. Errors of the same
+	// severity are ordered by code.
+	OrderSeverity
+
+	// Errors ordered by code.
+	OrderCode
+)
+
+var errorOrder atomic.Int32
+
+// SetErrorOrder sets the order in which the errors grouped by a MultiError
+// are rendered by its Error method and serialized as JSON or protobuf.
+// OrderSeverity and OrderCode make identical failures produce identical
+// payloads whatever the order they were collected in, for response caches
+// and golden tests. The order of Errors and Unwrap is left unchanged.
+func SetErrorOrder(order ErrorOrder) {
+	errorOrder.Store(int32(order))
+}
+
+// OrderedErrors returns the errors grouped by m in the order set by
+// SetErrorOrder, for packages serializing MultiErrors.
+func OrderedErrors(m MultiError) []error {
+	errs := m.Errors()
+	order := ErrorOrder(errorOrder.Load())
+	if order == OrderInsertion || len(errs) < 2 {
+		return errs
+	}
+	errs = append([]error(nil), errs...)
+	SortErrors(errs, order)
+	return errs
+}
+
+// SortErrors sorts errs in order. Errors equal for order, such as errors
+// with the same code for OrderCode, are ordered by their Error string, so
+// the result does not depend on the initial order of errs. OrderInsertion
+// leaves errs unchanged.
+func SortErrors(errs []error, order ErrorOrder) {
+	if order == OrderInsertion {
+		return
+	}
+
+	keys := make([]errorKey, len(errs))
+	for i, err := range errs {
+		keys[i] = errorKey{code: CodeOf(err), text: err.Error()}
+		if order == OrderSeverity {
+			keys[i].severity = SeverityOf(err)
+		}
+	}
+	sort.Sort(errorSorter{errs, keys})
+}
+
+// errorKey is the sort key of an error for SortErrors.
+type errorKey struct {
+	severity Severity
+	code     string
+	text     string
+}
+
+// errorSorter sorts errors along with their sort keys.
+type errorSorter struct {
+	errs []error
+	keys []errorKey
+}
+
+func (s errorSorter) Len() int { return len(s.errs) }
+
+func (s errorSorter) Less(i, j int) bool {
+	a, b := s.keys[i], s.keys[j]
+	if a.severity != b.severity {
+		return a.severity > b.severity
+	}
+	if a.code != b.code {
+		return a.code < b.code
+	}
+	return a.text < b.text
+}
+
+func (s errorSorter) Swap(i, j int) {
+	s.errs[i], s.errs[j] = s.errs[j], s.errs[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
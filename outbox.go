@@ -0,0 +1,107 @@
+package sneterr
+
+import "net/http"
+
+// Codes of the errors reporting that a business operation committed but
+// publishing its event failed, as opposed to errors reporting that the
+// whole operation failed.
+const (
+	// The event is stored in the outbox and will be delivered
+	// eventually by the outbox retrier.
+	ErrCodeEventPublishFailed = "EventPublishFailed"
+
+	// The event could not be stored in the outbox either: it will not
+	// be delivered without manual intervention.
+	ErrCodeEventLost = "EventLost"
+)
+
+// Field keys of the errors returned by NewPublishFailed and NewEventLost.
+const (
+	FieldEventID   = "event_id"
+	FieldEventType = "event_type"
+
+	// Marks errors reporting that the business operation committed.
+	FieldCommitted = "committed"
+
+	// Marks errors whose event will be delivered eventually.
+	FieldPendingDelivery = "pending_delivery"
+)
+
+func init() {
+	schema := Schema{
+		FieldEventID:         FieldString,
+		FieldEventType:       FieldString,
+		FieldCommitted:       FieldBool,
+		FieldPendingDelivery: FieldBool,
+	}
+	// The operation committed, so neither code is retryable and both are
+	// rendered with http.StatusInternalServerError rather than the 503 of
+	// CategoryUnavailable, which would invite clients to retry it.
+	Register(CodeInfo{
+		Code:     ErrCodeEventPublishFailed,
+		Category: CategoryUnavailable,
+		Severity: SeverityWarn,
+		Fields:   schema,
+		Status:   http.StatusInternalServerError,
+	})
+	Register(CodeInfo{
+		Code:     ErrCodeEventLost,
+		Category: CategoryInternal,
+		Severity: SeverityCritical,
+		Fields:   schema,
+	})
+}
+
+// NewPublishFailed returns an Error with ErrCodeEventPublishFailed,
+// wrapping err, reporting that the operation committed but publishing the
+// event eventID of type eventType failed while the event is safe in the
+// outbox, configured by opts.
+//
+// The operation must not be retried: Retryable reports false for the
+// error, while PendingDelivery reports true for the outbox retrier. A
+// handler whose operation committed should rather return its successful
+// response, reporting the failure with AddWarning if the client needs to
+// know that the event will be late.
+func NewPublishFailed(eventID, eventType string, err error, opts ...Option) Error {
+	b := newBaseError(ErrCodeEventPublishFailed,
+		"operation committed but publishing event "+eventType+" failed; it will be delivered later", err, "", 0)
+	b.fields = publishFields(eventID, eventType, true)
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// NewEventLost is like NewPublishFailed for an event which could not be
+// stored in the outbox either, and so will not be delivered.
+func NewEventLost(eventID, eventType string, err error, opts ...Option) Error {
+	b := newBaseError(ErrCodeEventLost, "operation committed but event "+eventType+" was lost", err, "", 0)
+	b.fields = publishFields(eventID, eventType, false)
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// publishFields returns the fields of a publish failure.
+func publishFields(eventID, eventType string, pending bool) Fields {
+	return Fields{
+		FieldEventID:         eventID,
+		FieldEventType:       eventType,
+		FieldCommitted:       true,
+		FieldPendingDelivery: pending,
+	}
+}
+
+// Committed reports whether err reports a failure which happened after
+// the business operation committed, such as a publish failure, so the
+// operation must not be retried. Errors from other processes keep the
+// marker through their JSON encoding.
+func Committed(err error) bool {
+	committed, _ := Field[bool](err, FieldCommitted)
+	return committed
+}
+
+// PendingDelivery reports whether err reports a publish failure whose
+// event will be delivered eventually, so consumers can expect it and the
+// outbox retrier should pick it up.
+func PendingDelivery(err error) bool {
+	pending, _ := Field[bool](err, FieldPendingDelivery)
+	return pending
+}
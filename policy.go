@@ -0,0 +1,111 @@
+package sneterr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A CodePolicy assigns values, such as rate limits or sampling rates, to
+// error codes by pattern. A pattern is either a code, matching only that
+// code, or a prefix followed by a "*", matching every code starting with
+// the prefix. "*" alone matches every code.
+//
+// Patterns are compiled into a prefix tree when the policy is loaded, so
+// matching a code takes a time proportional to its length however many
+// patterns the policy has. A CodePolicy is safe for concurrent use.
+type CodePolicy[V any] struct {
+	root policyNode[V]
+	n    int
+}
+
+// policyNode is a node of the prefix tree of a CodePolicy, for the prefix
+// leading to it.
+type policyNode[V any] struct {
+	children map[byte]*policyNode[V]
+
+	exact    V
+	hasExact bool
+
+	prefix    V
+	hasPrefix bool
+}
+
+// CompilePolicy returns a CodePolicy assigning the values of rules to
+// the codes matching their pattern. It fails if a pattern has a "*"
+// elsewhere than at its end.
+func CompilePolicy[V any](rules map[string]V) (*CodePolicy[V], error) {
+	p := &CodePolicy[V]{n: len(rules)}
+	for pattern, v := range rules {
+		prefix, wildcard := strings.CutSuffix(pattern, "*")
+		if strings.Contains(prefix, "*") {
+			return nil, fmt.Errorf("sneterr: invalid code pattern %q: %q is only allowed at its end", pattern, "*")
+		}
+
+		n := &p.root
+		for i := 0; i < len(prefix); i++ {
+			c, ok := n.children[prefix[i]]
+			if !ok {
+				if n.children == nil {
+					n.children = map[byte]*policyNode[V]{}
+				}
+				c = &policyNode[V]{}
+				n.children[prefix[i]] = c
+			}
+			n = c
+		}
+		if wildcard {
+			n.prefix, n.hasPrefix = v, true
+		} else {
+			n.exact, n.hasExact = v, true
+		}
+	}
+	return p, nil
+}
+
+// MustCompilePolicy is like CompilePolicy but panics if a pattern is
+// invalid. It is meant for policies initializing global variables.
+func MustCompilePolicy[V any](rules map[string]V) *CodePolicy[V] {
+	p, err := CompilePolicy(rules)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Len returns the number of patterns of the policy.
+func (p *CodePolicy[V]) Len() int {
+	return p.n
+}
+
+// Match returns the value of the pattern matching code. A code matches its
+// exact pattern first, then the pattern with the longest prefix.
+func (p *CodePolicy[V]) Match(code string) (V, bool) {
+	var (
+		best  V
+		found bool
+	)
+	n := &p.root
+	for i := 0; ; i++ {
+		if n.hasPrefix {
+			best, found = n.prefix, true
+		}
+		if i == len(code) {
+			if n.hasExact {
+				return n.exact, true
+			}
+			break
+		}
+		c, ok := n.children[code[i]]
+		if !ok {
+			break
+		}
+		n = c
+	}
+	return best, found
+}
+
+// MatchError returns the value of the pattern matching the code of err,
+// as returned by CodeOf.
+func (p *CodePolicy[V]) MatchError(err error) (V, bool) {
+	return p.Match(CodeOf(err))
+}
@@ -0,0 +1,188 @@
+package sneterr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Codes of preflight errors.
+const (
+	// Code of the MultiError returned by Preflight.Run.
+	ErrCodePreflight = "PreflightFailed"
+
+	// Code of the errors of the failed checks grouped by the MultiError.
+	ErrCodeDependencyUnavailable = "DependencyUnavailable"
+)
+
+// FieldDependency is the field key of the name of the dependency of the
+// errors of failed preflight checks.
+const FieldDependency = "dependency"
+
+// DefaultPreflightTimeout bounds each check of a Preflight without
+// Timeout.
+const DefaultPreflightTimeout = 10 * time.Second
+
+// Exit codes returned by ExitCode, from sysexits.h.
+const (
+	ExitUnavailable = 69
+	ExitConfig      = 78
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodePreflight,
+		Category: CategoryUnavailable,
+		Severity: SeverityCritical,
+	})
+	Register(CodeInfo{
+		Code:      ErrCodeDependencyUnavailable,
+		Category:  CategoryUnavailable,
+		Severity:  SeverityCritical,
+		Retryable: true,
+		Fields:    Schema{FieldDependency: FieldString},
+	})
+}
+
+// A Preflight checks the dependencies of a service, such as its database,
+// cache and queues, when it starts:
+//
+//	var pf sneterr.Preflight
+//	pf.Add("postgres", db.PingContext)
+//	pf.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+//	pf.RunOrExit(ctx, logger)
+//
+// The zero Preflight is ready to use.
+type Preflight struct {
+	// Timeout bounds each check. DefaultPreflightTimeout is used when
+	// zero.
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	checks []preflightCheck
+}
+
+type preflightCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// Add registers check for the dependency name.
+func (p *Preflight) Add(name string, check func(ctx context.Context) error) {
+	p.mu.Lock()
+	p.checks = append(p.checks, preflightCheck{name: name, check: check})
+	p.mu.Unlock()
+}
+
+// Run runs the registered checks concurrently, each with a context bounded
+// by the timeout of p, and returns a MultiError with ErrCodePreflight
+// grouping every failure, or nil if all the checks passed. The failures
+// are listed in the order the checks were added, each an Error with
+// ErrCodeDependencyUnavailable naming its dependency in the
+// FieldDependency field, wrapping the error of the check and recording
+// how long it took.
+//
+// A check which does not return once its context is done is reported as
+// timed out and left running.
+func (p *Preflight) Run(ctx context.Context) error {
+	p.mu.Lock()
+	checks := append([]preflightCheck(nil), p.checks...)
+	p.mu.Unlock()
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPreflightTimeout
+	}
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runCheck(ctx, c, timeout)
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%d of %d dependency checks failed", len(failed), len(checks))
+	return NewMultiError(ErrCodePreflight, msg, failed)
+}
+
+// runCheck runs c with a context bounded by timeout.
+func runCheck(ctx context.Context, c preflightCheck, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		err = FromContextErr(ctx, err)
+	}
+	return WithDuration(newError(1, ErrCodeDependencyUnavailable, c.name+" is unavailable", err,
+		WithField(FieldDependency, c.name)), time.Since(start))
+}
+
+// RunOrExit runs the checks like Run. If any fails it logs the error with
+// logger, or slog.Default() when nil, and exits the process with the
+// status returned by ExitCode.
+func (p *Preflight) RunOrExit(ctx context.Context, logger *slog.Logger) {
+	err := p.Run(ctx)
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, SeverityOf(err).Level(), "preflight failed", slog.Any("error", err))
+	os.Exit(ExitCode(err))
+}
+
+// ExitCode returns the exit status of a process failing to start with err:
+// 0 if err is nil, ExitConfig if the chain of err has a configuration
+// error, see ConfigErrors, ExitUnavailable if it has a failed preflight
+// check and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	code := 1
+	walk(err, func(e error) bool {
+		se, ok := e.(Error)
+		if !ok {
+			return true
+		}
+		switch se.Code() {
+		case ErrCodeConfig, ErrCodeConfigInvalid:
+			code = ExitConfig
+			return false
+		case ErrCodePreflight, ErrCodeDependencyUnavailable:
+			code = ExitUnavailable
+		}
+		return true
+	})
+	return code
+}
@@ -0,0 +1,190 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCodeRateLimited is the code of the errors returned by NewRateLimited.
+const ErrCodeRateLimited = "RateLimited"
+
+// FieldLimits is the field key of the limits of the errors returned by
+// NewRateLimited.
+const FieldLimits = "limits"
+
+// Scopes of the limits of rate limited errors.
+const (
+	LimitScopeTenant   = "tenant"
+	LimitScopeEndpoint = "endpoint"
+	LimitScopeGlobal   = "global"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:      ErrCodeRateLimited,
+		Category:  CategoryResourceExhausted,
+		Severity:  SeverityInfo,
+		Retryable: true,
+		Status:    http.StatusTooManyRequests,
+		Fields:    Schema{FieldLimits: FieldAny},
+	})
+}
+
+// A Limit is one of the rate limits applied to a request, with its state
+// when the request was rejected.
+type Limit struct {
+	// Scope of the limit, such as LimitScopeTenant.
+	Scope string
+
+	// What the limit applies to within its scope, such as the tenant ID or
+	// the endpoint. Empty for global limits.
+	Key string
+
+	// Number of requests allowed per Window, and left in the current one.
+	Limit     int
+	Remaining int
+	Window    time.Duration
+
+	// When the current window ends and Remaining is reset to Limit.
+	Reset time.Time
+
+	// Whether the request was rejected because of this limit.
+	Exceeded bool
+}
+
+// jsonLimit is the JSON representation of a Limit.
+type jsonLimit struct {
+	Scope     string    `json:"scope"`
+	Key       string    `json:"key,omitempty"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Window    string    `json:"window,omitempty"`
+	Reset     time.Time `json:"reset,omitzero"`
+	Exceeded  bool      `json:"exceeded,omitempty"`
+}
+
+// MarshalJSON encodes the limit as a JSON object, with its window as a
+// duration string such as "1m0s".
+func (l Limit) MarshalJSON() ([]byte, error) {
+	j := jsonLimit{
+		Scope:     l.Scope,
+		Key:       l.Key,
+		Limit:     l.Limit,
+		Remaining: l.Remaining,
+		Reset:     l.Reset,
+		Exceeded:  l.Exceeded,
+	}
+	if l.Window != 0 {
+		j.Window = l.Window.String()
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a limit encoded by MarshalJSON.
+func (l *Limit) UnmarshalJSON(data []byte) error {
+	var j jsonLimit
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*l = Limit{
+		Scope:     j.Scope,
+		Key:       j.Key,
+		Limit:     j.Limit,
+		Remaining: j.Remaining,
+		Reset:     j.Reset,
+		Exceeded:  j.Exceeded,
+	}
+	if j.Window != "" {
+		l.Window, _ = time.ParseDuration(j.Window)
+	}
+	return nil
+}
+
+// String returns a short description of the limit, such as "tenant acme:
+// 100 per 1m0s".
+func (l Limit) String() string {
+	var sb strings.Builder
+	sb.WriteString(l.Scope)
+	if l.Key != "" {
+		sb.WriteByte(' ')
+		sb.WriteString(l.Key)
+	}
+	sb.WriteString(": ")
+	sb.WriteString(strconv.Itoa(l.Limit))
+	if l.Window != 0 {
+		sb.WriteString(" per ")
+		sb.WriteString(l.Window.String())
+	}
+	return sb.String()
+}
+
+// NewRateLimited returns an Error with ErrCodeRateLimited reporting that a
+// request was rejected by the limits marked Exceeded among limits,
+// rendered with http.StatusTooManyRequests. Every limit applied to the
+// request, per tenant, per endpoint or global, is attached in the
+// FieldLimits field with its reset time, so clients know which limit they
+// hit and when each resets rather than a single retry delay.
+func NewRateLimited(limits []Limit, opts ...Option) Error {
+	limits = append([]Limit(nil), limits...)
+
+	var exceeded []string
+	for _, l := range limits {
+		if l.Exceeded {
+			exceeded = append(exceeded, l.String())
+		}
+	}
+	msg := "rate limit exceeded"
+	if len(exceeded) > 0 {
+		msg += ": " + strings.Join(exceeded, ", ")
+	}
+
+	b := newBaseError(ErrCodeRateLimited, msg, nil, "", 0)
+	b.fields = Fields{FieldLimits: limits}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// RateLimits returns the limits attached to err by NewRateLimited,
+// including when err was decoded from a response by FromResponse.
+func RateLimits(err error) ([]Limit, bool) {
+	switch v := FieldsOf(err)[FieldLimits].(type) {
+	case []Limit:
+		return append([]Limit(nil), v...), true
+	case []interface{}:
+		data, mErr := json.Marshal(v)
+		if mErr != nil {
+			return nil, false
+		}
+		var limits []Limit
+		if json.Unmarshal(data, &limits) != nil {
+			return nil, false
+		}
+		return limits, true
+	}
+	return nil, false
+}
+
+// RetryAfter returns how long to wait before retrying the request which
+// failed with err, until every exceeded limit attached by NewRateLimited
+// is reset. It reports false if err has no exceeded limit with a reset
+// time.
+func RetryAfter(err error) (time.Duration, bool) {
+	limits, _ := RateLimits(err)
+	var reset time.Time
+	for _, l := range limits {
+		if l.Exceeded && l.Reset.After(reset) {
+			reset = l.Reset
+		}
+	}
+	if reset.IsZero() {
+		return 0, false
+	}
+	d := time.Until(reset)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
@@ -0,0 +1,179 @@
+package sneterr
+
+import (
+	"sync"
+	"time"
+)
+
+// A RetentionPolicy bounds how long and how much a Recorder keeps.
+// Zero values mean no limit.
+type RetentionPolicy struct {
+	// Maximum age of a recorded error.
+	MaxAge time.Duration
+
+	// Maximum total size of the recorded errors, measured as the length of
+	// their Error string. The oldest errors are evicted first.
+	MaxBytes int64
+
+	// Maximum age of recorded errors by severity, overriding MaxAge. A
+	// zero duration in the map still means no limit for that severity.
+	SeverityMaxAge map[Severity]time.Duration
+}
+
+// maxAge returns the maximum age of errors with severity s.
+func (p RetentionPolicy) maxAge(s Severity) time.Duration {
+	if d, ok := p.SeverityMaxAge[s]; ok {
+		return d
+	}
+	return p.MaxAge
+}
+
+// RecorderStats reports the content of a Recorder and the work of its
+// janitor.
+type RecorderStats struct {
+	// Number of errors currently recorded.
+	Entries int
+
+	// Total size of the recorded errors.
+	Bytes int64
+
+	// Number of errors evicted by the retention policy so far.
+	Evicted int64
+
+	// When the retention policy was last enforced.
+	LastRun time.Time
+}
+
+// A RecordedError is an error kept by a Recorder.
+type RecordedError struct {
+	Err        Error
+	RecordedAt time.Time
+
+	severity Severity
+	size     int64
+}
+
+// A Recorder keeps the recently created errors in memory for diagnostics,
+// within the limits of a RetentionPolicy. It is safe for concurrent use.
+type Recorder struct {
+	policy RetentionPolicy
+
+	mu      sync.Mutex
+	entries []RecordedError
+	bytes   int64
+	evicted int64
+	lastRun time.Time
+}
+
+// NewRecorder returns a Recorder enforcing policy.
+func NewRecorder(policy RetentionPolicy) *Recorder {
+	return &Recorder{policy: policy}
+}
+
+// Record adds err to the recorder. MaxBytes is enforced immediately, the
+// age limits when Enforce runs.
+func (r *Recorder) Record(err Error) {
+	e := RecordedError{
+		Err:        err,
+		RecordedAt: time.Now(),
+		severity:   SeverityOf(err),
+		size:       int64(len(err.Error())),
+	}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.bytes += e.size
+	r.enforceBytes()
+	r.mu.Unlock()
+}
+
+// Hook returns a Hook recording every created error.
+func (r *Recorder) Hook() Hook {
+	return r.Record
+}
+
+// Errors returns the recorded errors, oldest first.
+func (r *Recorder) Errors() []RecordedError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedError(nil), r.entries...)
+}
+
+// Stats returns the current state of the recorder.
+func (r *Recorder) Stats() RecorderStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RecorderStats{
+		Entries: len(r.entries),
+		Bytes:   r.bytes,
+		Evicted: r.evicted,
+		LastRun: r.lastRun,
+	}
+}
+
+// Enforce evicts the errors exceeding the retention policy.
+func (r *Recorder) Enforce() {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.entries[:0]
+	for _, e := range r.entries {
+		if max := r.policy.maxAge(e.severity); max > 0 && now.Sub(e.RecordedAt) > max {
+			r.bytes -= e.size
+			r.evicted++
+			continue
+		}
+		kept = append(kept, e)
+	}
+	clear(r.entries[len(kept):])
+	r.entries = kept
+
+	r.enforceBytes()
+	r.lastRun = now
+}
+
+// enforceBytes evicts the oldest errors until MaxBytes is satisfied.
+func (r *Recorder) enforceBytes() {
+	if r.policy.MaxBytes <= 0 {
+		return
+	}
+	n := 0
+	for n < len(r.entries) && r.bytes > r.policy.MaxBytes {
+		r.bytes -= r.entries[n].size
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	r.evicted += int64(n)
+	clear(r.entries[:n])
+	r.entries = r.entries[n:]
+}
+
+// StartJanitor calls Enforce every interval in a background goroutine
+// until the returned stop function is called.
+func (r *Recorder) StartJanitor(interval time.Duration) (stop func()) {
+	return startJanitor(interval, r.Enforce)
+}
+
+// startJanitor calls run every interval until the returned function is
+// called.
+func startJanitor(interval time.Duration, run func()) func() {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				run()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { once.Do(func() { close(done) }) }
+}
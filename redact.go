@@ -0,0 +1,149 @@
+package sneterr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// A URLRedactor removes personal and secret data from URLs before they are
+// attached to errors. Credentials and fragments, which may carry tokens
+// such as "#access_token=", are always removed.
+type URLRedactor struct {
+	// Names of the query parameters whose values are replaced with
+	// RedactedValue, compared case-insensitively.
+	QueryParams []string
+
+	// Patterns of the path segments holding identifiers, replaced with
+	// ":id", or with a hash of the segment when HashIDs is set.
+	IDPatterns []*regexp.Regexp
+
+	// Whether identifiers are replaced with a short hash instead of
+	// ":id", so the failures on the same resource can still be told apart.
+	HashIDs bool
+}
+
+// DefaultURLRedactor is the URLRedactor used until SetURLRedactor is
+// called. It redacts the usual token and password parameters and the path
+// segments which are numbers, UUIDs or long hexadecimal strings.
+var DefaultURLRedactor = &URLRedactor{
+	QueryParams: []string{
+		"access_token", "api_key", "apikey", "auth", "code", "key",
+		"password", "secret", "sig", "signature", "token",
+	},
+	IDPatterns: []*regexp.Regexp{
+		regexp.MustCompile(`^[0-9]+$`),
+		regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+		regexp.MustCompile(`^(?i)[0-9a-f]{16,}$`),
+	},
+}
+
+var urlRedactor atomic.Pointer[URLRedactor]
+
+// SetURLRedactor sets the URLRedactor applied to the fields of errors. A
+// nil r restores DefaultURLRedactor.
+func SetURLRedactor(r *URLRedactor) {
+	urlRedactor.Store(r)
+}
+
+// Redact returns u as a string without its credentials nor fragment, with
+// the values of the configured query parameters replaced with
+// RedactedValue and its identifier path segments replaced.
+func (r *URLRedactor) Redact(u *url.URL) string {
+	c := *u
+	c.User = nil
+	c.Fragment, c.RawFragment = "", ""
+	c.RawQuery = r.redactQuery(u.RawQuery)
+	if len(r.IDPatterns) > 0 {
+		segments := strings.Split(u.EscapedPath(), "/")
+		for i, s := range segments {
+			if s != "" && r.isID(s) {
+				segments[i] = r.replaceID(s)
+			}
+		}
+		c.RawPath = strings.Join(segments, "/")
+		c.Path, _ = url.PathUnescape(c.RawPath)
+	}
+	return c.String()
+}
+
+// redactQuery returns the raw query q with the values of the configured
+// parameters replaced, keeping the order and encoding of the others.
+func (r *URLRedactor) redactQuery(q string) string {
+	if q == "" || len(r.QueryParams) == 0 {
+		return q
+	}
+	pairs := strings.Split(q, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		for _, p := range r.QueryParams {
+			if strings.EqualFold(name, p) {
+				pairs[i] = key + "=" + RedactedValue
+				break
+			}
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func (r *URLRedactor) isID(segment string) bool {
+	for _, re := range r.IDPatterns {
+		if re.MatchString(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *URLRedactor) replaceID(segment string) string {
+	if !r.HashIDs {
+		return ":id"
+	}
+	sum := sha256.Sum256([]byte(segment))
+	return ":" + hex.EncodeToString(sum[:4])
+}
+
+// redactField returns the value attached to errors for the field value v:
+// URLs and HTTP requests are replaced with their URL redacted by the
+// URLRedactor set with SetURLRedactor, prefixed with the method for
+// requests. Other values are returned as they are.
+func redactField(v interface{}) interface{} {
+	r := urlRedactor.Load()
+	if r == nil {
+		r = DefaultURLRedactor
+	}
+	switch v := v.(type) {
+	case *url.URL:
+		if v != nil {
+			return r.Redact(v)
+		}
+	case url.URL:
+		return r.Redact(&v)
+	case *http.Request:
+		if v != nil && v.URL != nil {
+			return v.Method + " " + r.Redact(v.URL)
+		}
+	}
+	return v
+}
+
+// redactFields returns a copy of fields with their values redacted by
+// redactField, or nil if fields is empty.
+func redactFields(fields Fields) Fields {
+	if len(fields) == 0 {
+		return nil
+	}
+	c := make(Fields, len(fields))
+	for k, v := range fields {
+		c[k] = redactField(v)
+	}
+	return c
+}
@@ -0,0 +1,141 @@
+package sneterr
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrCodeResponse is the code of errors returned by FromResponse when the
+// response body does not describe an error.
+const ErrCodeResponse = "ResponseError"
+
+// maxResponseBody is the number of bytes of a response body FromResponse
+// reads to decode the error.
+const maxResponseBody = 1 << 20
+
+// A RequestFailure is an Error describing a failed request to a remote
+// service.
+type RequestFailure interface {
+	Error
+
+	// The status code of the HTTP response.
+	StatusCode() int
+
+	// The request ID returned by the service for a request failure. This will
+	// be empty if no request ID is available such as the request failed due
+	// to a connection error.
+	RequestID() string
+}
+
+// NewRequestFailure returns a RequestFailure wrapping err with the status
+// code and request ID of the failed request.
+func NewRequestFailure(err Error, statusCode int, reqID string) RequestFailure {
+	return &requestError{sneterror: err, statusCode: statusCode, requestID: reqID}
+}
+
+// sneterror is an alias so Error can be embedded in requestError.
+type sneterror Error
+
+// requestError wraps an Error with the details of a failed request.
+type requestError struct {
+	sneterror
+	statusCode int
+	requestID  string
+	rawBody    []byte
+}
+
+// StatusCode returns the status code of the HTTP response.
+func (r requestError) StatusCode() int {
+	return r.statusCode
+}
+
+// RequestID returns the request ID of the failed request.
+func (r requestError) RequestID() string {
+	return r.requestID
+}
+
+// Unwrap returns the wrapped Error.
+func (r requestError) Unwrap() error {
+	return r.sneterror
+}
+
+// Frozen reports whether the wrapped Error is frozen, as it is when
+// decoded by FromResponse.
+func (r requestError) Frozen() bool {
+	return Frozen(r.sneterror)
+}
+
+// Annotated reports whether the wrapped Error is an annotation.
+func (r requestError) Annotated() bool {
+	return Annotated(r.sneterror)
+}
+
+// A ResponseOption configures FromResponse.
+type ResponseOption func(*responseOptions)
+
+type responseOptions struct {
+	rawBodyLimit int
+}
+
+// WithRawBody makes FromResponse keep a copy of the first limit bytes of
+// the response body, retrievable with RawBody.
+func WithRawBody(limit int) ResponseOption {
+	return func(o *responseOptions) {
+		o.rawBodyLimit = limit
+	}
+}
+
+// FromResponse returns a RequestFailure describing the error response resp.
+//
+// The body is expected to be the JSON encoding of an Error, as produced by
+// its MarshalJSON method. If it is not, the error has ErrCodeResponse and
+// the decoding error as its original error. The request ID is taken from
+// the X-Request-Id header. FromResponse reads the body but does not close
+// it.
+func FromResponse(resp *http.Response, opts ...ResponseOption) RequestFailure {
+	var o responseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	var e Error
+	var j jsonError
+	switch err := json.Unmarshal(body, &j); {
+	case readErr != nil:
+		e = newBaseError(ErrCodeResponse, "failed to read error response", readErr, "", 0)
+	case err != nil:
+		e = newBaseError(ErrCodeResponse, http.StatusText(resp.StatusCode), err, "", 0)
+	case j.Code == "":
+		e = newBaseError(ErrCodeResponse, http.StatusText(resp.StatusCode),
+			errors.New("response body has no error code"), "", 0)
+	default:
+		e = fromJSON(&j).(Error)
+	}
+
+	r := &requestError{
+		sneterror:  e,
+		statusCode: resp.StatusCode,
+		requestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if o.rawBodyLimit > 0 {
+		if len(body) > o.rawBodyLimit {
+			body = body[:o.rawBodyLimit]
+		}
+		r.rawBody = append([]byte(nil), body...)
+	}
+	return r
+}
+
+// RawBody returns the response body kept by FromResponse when called with
+// WithRawBody.
+func RawBody(err error) ([]byte, bool) {
+	var r *requestError
+	if !errors.As(err, &r) || r.rawBody == nil {
+		return nil, false
+	}
+	return r.rawBody, true
+}
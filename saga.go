@@ -0,0 +1,191 @@
+package sneterr
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// Codes of the errors returned by NewSagaError.
+const (
+	// The saga failed and every compensation succeeded: the system is
+	// consistent again.
+	ErrCodeSagaFailed = "SagaFailed"
+
+	// The saga failed and some compensation failed too: the system needs
+	// manual reconciliation.
+	ErrCodeSagaCompensationFailed = "SagaCompensationFailed"
+)
+
+func init() {
+	Register(CodeInfo{Code: ErrCodeSagaFailed, Category: CategoryInternal})
+	Register(CodeInfo{Code: ErrCodeSagaCompensationFailed, Category: CategoryInternal, Severity: SeverityCritical})
+}
+
+// A Compensation is the outcome of a compensation step run after a saga
+// failed.
+type Compensation struct {
+	// Name of the step compensated.
+	Step string
+
+	// Why the compensation failed, or nil if it succeeded.
+	Err Error
+}
+
+// Succeeded reports whether the compensation succeeded.
+func (c Compensation) Succeeded() bool {
+	return c.Err == nil
+}
+
+// A SagaError reports a failed saga: the step which failed and the
+// outcome of each compensation step run to undo the steps before it.
+type SagaError interface {
+	Error
+
+	// Returns the name of the saga.
+	Saga() string
+
+	// Returns the name of the step which failed.
+	Step() string
+
+	// Returns the outcomes of the compensation steps, in the order they
+	// ran.
+	Compensations() []Compensation
+
+	// Reports whether every compensation succeeded.
+	Compensated() bool
+}
+
+// NewSagaError returns a SagaError for the saga which failed at step with
+// err, and the outcomes of its compensations. Its code is
+// ErrCodeSagaFailed, or ErrCodeSagaCompensationFailed if a compensation
+// failed. The error wraps err.
+//
+// Its JSON encoding lists the compensations with their status and error,
+// for the operations dashboard to show what needs reconciliation.
+func NewSagaError(saga, step string, err error, compensations []Compensation) SagaError {
+	s := &sagaError{saga: saga, step: step, compensations: append([]Compensation(nil), compensations...)}
+
+	code, msg := ErrCodeSagaFailed, "saga "+saga+" failed at step "+step
+	if !s.Compensated() {
+		code = ErrCodeSagaCompensationFailed
+		msg += " and " + strconv.Itoa(s.failedCompensations()) + " compensation(s) failed"
+	}
+	s.sneterror = finishError(2, newBaseError(code, msg, err, "", 0))
+	return s
+}
+
+// sagaError wraps the Error of a failed saga with its compensations.
+type sagaError struct {
+	sneterror
+	saga          string
+	step          string
+	compensations []Compensation
+}
+
+// Saga returns the name of the saga.
+func (s sagaError) Saga() string {
+	return s.saga
+}
+
+// Step returns the name of the step which failed.
+func (s sagaError) Step() string {
+	return s.step
+}
+
+// Compensations returns the outcomes of the compensation steps.
+func (s sagaError) Compensations() []Compensation {
+	return append([]Compensation(nil), s.compensations...)
+}
+
+// Compensated reports whether every compensation succeeded.
+func (s sagaError) Compensated() bool {
+	return s.failedCompensations() == 0
+}
+
+func (s sagaError) failedCompensations() int {
+	n := 0
+	for _, c := range s.compensations {
+		if !c.Succeeded() {
+			n++
+		}
+	}
+	return n
+}
+
+// Unwrap returns the wrapped Error.
+func (s sagaError) Unwrap() error {
+	return s.sneterror
+}
+
+// Frozen reports whether the wrapped Error is frozen, as it is when
+// decoded by UnmarshalError.
+func (s sagaError) Frozen() bool {
+	return Frozen(s.sneterror)
+}
+
+// Annotated reports whether the wrapped Error is an annotation.
+func (s sagaError) Annotated() bool {
+	return Annotated(s.sneterror)
+}
+
+// LogValue returns the error as a slog group of the wrapped Error, the
+// saga, the failed step and the compensations.
+func (s sagaError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Any("error", s.sneterror),
+		slog.String("saga", s.saga),
+		slog.String("step", s.step),
+	}
+	for _, c := range s.compensations {
+		if c.Err == nil {
+			attrs = append(attrs, slog.String("compensation."+c.Step, "succeeded"))
+		} else {
+			attrs = append(attrs, slog.Any("compensation."+c.Step, c.Err))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// jsonSaga is the JSON representation of the saga of a SagaError.
+type jsonSaga struct {
+	Name          string             `json:"name"`
+	Step          string             `json:"step"`
+	Compensations []jsonCompensation `json:"compensations,omitempty"`
+}
+
+// jsonCompensation is the JSON representation of a Compensation.
+type jsonCompensation struct {
+	Step   string     `json:"step"`
+	Status string     `json:"status"`
+	Error  *jsonError `json:"error,omitempty"`
+}
+
+// toJSON returns the JSON representation of the saga of s.
+func (s sagaError) toJSON() *jsonSaga {
+	j := &jsonSaga{Name: s.saga, Step: s.step}
+	for _, c := range s.compensations {
+		jc := jsonCompensation{Step: c.Step, Status: "succeeded"}
+		if c.Err != nil {
+			jc.Status = "failed"
+			jc.Error = toJSON(c.Err)
+		}
+		j.Compensations = append(j.Compensations, jc)
+	}
+	return j
+}
+
+// sagaFromJSON rebuilds the SagaError wrapping e described by j.
+func sagaFromJSON(e Error, j *jsonSaga) *sagaError {
+	s := &sagaError{sneterror: e, saga: j.Name, step: j.Step}
+	for _, jc := range j.Compensations {
+		c := Compensation{Step: jc.Step}
+		if jc.Status != "succeeded" {
+			c.Err, _ = fromJSON(jc.Error).(Error)
+			if c.Err == nil {
+				c.Err = newBaseError(ErrCodeUnknown, "compensation failed", nil, "", 0)
+			}
+		}
+		s.compensations = append(s.compensations, c)
+	}
+	return s
+}
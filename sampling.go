@@ -0,0 +1,130 @@
+package sneterr
+
+import (
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SampledHook returns a Hook calling h for a random fraction rate of the
+// errors, between 0 (none) and 1 (all). Errors of requests being debugged,
+// see WithDebug, are never dropped.
+func SampledHook(h Hook, rate float64) Hook {
+	return func(err Error) {
+		if rate >= 1 || rand.Float64() < rate || Debug(err) {
+			h(err)
+		}
+	}
+}
+
+// A RateLimit allows PerSecond calls per second on average, with bursts of
+// up to Burst calls. A zero PerSecond means no limit.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// RateLimitedHook returns a Hook calling h within a rate limit for each
+// error code, so a flood of errors with one code does not flood the sinks
+// nor starve the other codes. Codes matching a pattern of perCode, as in a
+// CodePolicy, get its limit, the others def. Errors over the limit are
+// dropped, except those of requests being debugged, see WithDebug.
+//
+// RateLimitedHook panics if a pattern of perCode is invalid.
+func RateLimitedHook(h Hook, def RateLimit, perCode map[string]RateLimit) Hook {
+	l := &codeLimiter{def: def, perCode: MustCompilePolicy(perCode), buckets: map[string]*bucket{}}
+	return func(err Error) {
+		if Debug(err) || l.allow(err.Code(), time.Now()) {
+			h(err)
+		}
+	}
+}
+
+// maxBuckets bounds the number of token buckets a RateLimitedHook keeps,
+// since codes may be built from input such as the status of a remote
+// service.
+const maxBuckets = 4096
+
+// codeLimiter keeps a token bucket per error code. When maxBuckets is
+// reached the buckets which refilled are dropped, as they allow as much as
+// new ones, see evict.
+type codeLimiter struct {
+	def     RateLimit
+	perCode *CodePolicy[RateLimit]
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (l *codeLimiter) allow(code string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[code]
+	if !ok {
+		limit, ok := l.perCode.Match(code)
+		if !ok {
+			limit = l.def
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		if len(l.buckets) >= maxBuckets {
+			l.evict(now)
+		}
+		l.buckets[code] = b
+	}
+	if b.limit.PerSecond <= 0 {
+		return true
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * b.limit.PerSecond
+	if max := float64(b.limit.Burst); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// evict drops the buckets which are full at now. If that leaves more than
+// three quarters of maxBuckets, the least recently used ones are dropped
+// too, so the next evictions are as far off.
+func (l *codeLimiter) evict(now time.Time) {
+	codes := make([]string, 0, len(l.buckets))
+	for code, b := range l.buckets {
+		if b.full(now) {
+			delete(l.buckets, code)
+		} else {
+			codes = append(codes, code)
+		}
+	}
+
+	excess := len(l.buckets) - maxBuckets*3/4
+	if excess <= 0 {
+		return
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return l.buckets[codes[i]].last.Before(l.buckets[codes[j]].last)
+	})
+	for _, code := range codes[:excess] {
+		delete(l.buckets, code)
+	}
+}
+
+// full reports whether b is refilled at now.
+func (b *bucket) full(now time.Time) bool {
+	return b.limit.PerSecond <= 0 ||
+		b.tokens+now.Sub(b.last).Seconds()*b.limit.PerSecond >= float64(b.limit.Burst)
+}
@@ -0,0 +1,34 @@
+package sneterr
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCodeLimiterEviction(t *testing.T) {
+	l := &codeLimiter{
+		def:     RateLimit{PerSecond: 1, Burst: 1},
+		perCode: MustCompilePolicy[RateLimit](nil),
+		buckets: map[string]*bucket{},
+	}
+	now := time.Now()
+
+	if !l.allow("Flood", now) || l.allow("Flood", now) {
+		t.Fatalf("allow() does not limit code Flood")
+	}
+	for i := 0; i < 3*maxBuckets; i++ {
+		l.allow("Code"+strconv.Itoa(i), now)
+		if len(l.buckets) > maxBuckets {
+			t.Fatalf("%d buckets kept, want at most %d", len(l.buckets), maxBuckets)
+		}
+	}
+
+	later := now.Add(2 * time.Second)
+	for i := 0; i < maxBuckets; i++ {
+		l.allow("Later"+strconv.Itoa(i), later)
+	}
+	if _, ok := l.buckets["Later0"]; !ok {
+		t.Errorf("bucket Later0 was evicted while the refilled buckets were kept")
+	}
+}
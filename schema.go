@@ -0,0 +1,132 @@
+package sneterr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// A FieldType is the type expected for the value of a field.
+type FieldType int
+
+// Field types of a Schema.
+const (
+	FieldAny FieldType = iota
+	FieldString
+	FieldInt
+	FieldFloat
+	FieldBool
+	FieldTime
+)
+
+var fieldTypeNames = [...]string{
+	FieldAny:    "any",
+	FieldString: "string",
+	FieldInt:    "int",
+	FieldFloat:  "float",
+	FieldBool:   "bool",
+	FieldTime:   "time",
+}
+
+// String returns the name of the field type.
+func (t FieldType) String() string {
+	if t < 0 || int(t) >= len(fieldTypeNames) {
+		return "unknown"
+	}
+	return fieldTypeNames[t]
+}
+
+// accepts reports whether v is a valid value for the field type.
+func (t FieldType) accepts(v interface{}) bool {
+	switch t {
+	case FieldAny:
+		return true
+	case FieldTime:
+		_, ok := v.(time.Time)
+		return ok
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String:
+		return t == FieldString
+	case reflect.Bool:
+		return t == FieldBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t == FieldInt || t == FieldFloat
+	case reflect.Float32, reflect.Float64:
+		return t == FieldFloat
+	}
+	return false
+}
+
+// A Schema declares the type of the fields of errors with a code, through
+// CodeInfo.Fields. Fields not in the schema are not checked.
+type Schema map[string]FieldType
+
+var devMode atomic.Bool
+
+// SetDevMode sets whether fields are checked against the schema of their
+// error code when they are attached. In dev mode a field with a value of
+// the wrong type makes the attaching call panic, so mistakes surface in
+// development and tests. Dev mode is disabled by default.
+func SetDevMode(enabled bool) {
+	devMode.Store(enabled)
+}
+
+// ValidateFields checks fields against the schema registered for code. It
+// returns nil if the code has no schema.
+func ValidateFields(code string, fields Fields) error {
+	info, ok := Lookup(code)
+	if !ok || len(info.Fields) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for k, v := range fields {
+		t, ok := info.Fields[k]
+		if ok && v != nil && !t.accepts(v) {
+			errs = append(errs, fmt.Errorf("field %q of %s is %T, want %s", k, code, v, t))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkFields panics in dev mode if fields do not match the schema of
+// code.
+func checkFields(code string, fields Fields) {
+	if !devMode.Load() {
+		return
+	}
+	if err := ValidateFields(code, fields); err != nil {
+		panic("sneterr: " + err.Error())
+	}
+}
+
+// Field returns the value of the field key of err converted to T, and
+// whether it is set with a value convertible to T. Numbers are converted
+// between numeric types, so fields decoded from JSON can be read with
+// their declared type.
+func Field[T any](err error, key string) (T, bool) {
+	var zero T
+	v, ok := FieldsOf(err)[key]
+	if !ok {
+		return zero, false
+	}
+	if t, ok := v.(T); ok {
+		return t, true
+	}
+
+	rv := reflect.ValueOf(v)
+	rt := reflect.TypeOf(zero)
+	if rv.IsValid() && rt != nil && isNumber(rv.Kind()) && isNumber(rt.Kind()) {
+		return rv.Convert(rt).Interface().(T), true
+	}
+	return zero, false
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
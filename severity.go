@@ -0,0 +1,78 @@
+package sneterr
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// Severity classifies how serious an error is.
+type Severity int
+
+// Severity levels, from least to most serious. The zero value means the
+// severity was not set.
+const (
+	SeverityUnset Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = [...]string{
+	SeverityUnset:    "unset",
+	SeverityDebug:    "debug",
+	SeverityInfo:     "info",
+	SeverityWarn:     "warn",
+	SeverityError:    "error",
+	SeverityCritical: "critical",
+}
+
+// String returns the lower case name of the severity.
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// Level returns the slog level used to log errors of the severity.
+func (s Severity) Level() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarn:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	}
+	return slog.LevelError
+}
+
+// SeverityOf returns the severity of err.
+//
+// An error implementing Severity() Severity reports its own severity.
+// Otherwise the severity registered for the error's code is used, and
+// SeverityError if neither is set. A nil error has SeverityUnset.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return SeverityUnset
+	}
+
+	var s interface{ Severity() Severity }
+	if errors.As(err, &s) {
+		if sev := s.Severity(); sev != SeverityUnset {
+			return sev
+		}
+	}
+
+	var e Error
+	if errors.As(err, &e) {
+		if info, ok := Lookup(e.Code()); ok && info.Severity != SeverityUnset {
+			return info.Severity
+		}
+	}
+	return SeverityError
+}
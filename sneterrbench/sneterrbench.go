@@ -0,0 +1,342 @@
+// Package sneterrbench publishes the benchmarks of sneterr, and helpers to
+// run them in the CI of downstream services with performance thresholds.
+//
+// A service can track the benchmarks with its own go test -bench run:
+//
+//	func BenchmarkSneterr(b *testing.B) {
+//		sneterrbench.RunAll(b)
+//	}
+//
+// or fail its tests when they regress:
+//
+//	func TestSneterrPerformance(t *testing.T) {
+//		sneterrbench.Check(t, sneterrbench.Thresholds{
+//			"New":  {MaxAllocsPerOp: 2},
+//			"Wrap": {MaxNsPerOp: 2 * time.Microsecond},
+//		})
+//	}
+//
+// The benchmarks run with the hooks registered by the service, so that
+// the cost of creating errors includes them.
+package sneterrbench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// templatedCode is the code of the error created by the NewT benchmark.
+const templatedCode = "sneterrbench.Templated"
+
+func init() {
+	sneterr.Template(templatedCode, "order {{.OrderID}} was not found for customer {{.CustomerID}}")
+}
+
+// A Benchmark is one benchmark of the suite.
+type Benchmark struct {
+	Name string
+	F    func(b *testing.B)
+}
+
+var (
+	cause  = errors.New("connection reset by peer")
+	fields = sneterr.Fields{"order_id": "o-1234", "attempt": 3}
+	sample = sneterr.WithFields(
+		sneterr.Wrap(sneterr.New("NotFound", "order was not found", sneterr.WithCause(cause)), "Lookup", "cannot look up order"),
+		fields)
+	defined = sneterr.Define("Rejected", "request rejected by policy",
+		sneterr.WithField("policy", "geo-block"))
+
+	// The sinks keep the results of the benchmarks alive. The results of
+	// CodeOf and CategoryOf have their own, as storing them in an interface
+	// would allocate.
+	sink         interface{}
+	codeSink     string
+	categorySink sneterr.Category
+)
+
+// Benchmarks returns the benchmarks of the suite, sorted by name:
+//
+//	Build          creation of an error with a Builder
+//	CategoryOf     classification of an error by category
+//	CodeOf         classification of an error by code
+//	DefinedError   rendering of an error returned by Define with Error
+//	DefinedJSON    JSON encoding of an error returned by Define
+//	Error          rendering of an error with Error
+//	FormatVerbose  rendering of an error with %+v
+//	HookDispatch   dispatch of an error through SeverityHook and SampledHook
+//	MarshalJSON    JSON encoding of an error
+//	New            creation of an error
+//	NewT           creation of an error from a message template
+//	WithFields     attaching fields to an error
+//	Wrap           wrapping of an error
+//	WrapSprintf    wrapping of an error with a message from fmt.Sprintf
+//	Wrapf          wrapping of an error with a lazily formatted message
+func Benchmarks() []Benchmark {
+	bs := []Benchmark{
+		{"New", benchNew},
+		{"NewT", benchNewT},
+		{"Build", benchBuild},
+		{"Wrap", benchWrap},
+		{"WrapSprintf", benchWrapSprintf},
+		{"Wrapf", benchWrapf},
+		{"WithFields", benchWithFields},
+		{"Error", benchError},
+		{"FormatVerbose", benchFormatVerbose},
+		{"MarshalJSON", benchMarshalJSON},
+		{"DefinedError", benchDefinedError},
+		{"DefinedJSON", benchDefinedJSON},
+		{"HookDispatch", benchHookDispatch},
+		{"CodeOf", benchCodeOf},
+		{"CategoryOf", benchCategoryOf},
+	}
+	sort.Slice(bs, func(i, j int) bool { return bs[i].Name < bs[j].Name })
+	return bs
+}
+
+func benchNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.New("NotFound", "order was not found", sneterr.WithCause(cause))
+	}
+}
+
+func benchNewT(b *testing.B) {
+	params := sneterr.Fields{"OrderID": "o-1234", "CustomerID": "c-42"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.NewT(templatedCode, params, sneterr.WithCause(cause))
+	}
+}
+
+func benchBuild(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.Build("NotFound", "order was not found").Cause(cause).Err()
+	}
+}
+
+func benchWrap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.Wrap(cause, "Lookup", "cannot look up order")
+	}
+}
+
+func benchWrapSprintf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.Wrap(cause, "Lookup", fmt.Sprintf("cannot look up order %d", i))
+	}
+}
+
+func benchWrapf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.Wrapf(cause, "Lookup", nil, "cannot look up order %d", i)
+	}
+}
+
+func benchWithFields(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sneterr.WithFields(sample, fields)
+	}
+}
+
+func benchError(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = sample.Error()
+	}
+}
+
+func benchFormatVerbose(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fmt.Fprintf(io.Discard, "%+v", sample)
+	}
+}
+
+func benchMarshalJSON(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink, _ = json.Marshal(sample)
+	}
+}
+
+func benchDefinedError(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = defined.Error()
+	}
+}
+
+func benchDefinedJSON(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink, _ = json.Marshal(defined)
+	}
+}
+
+func benchHookDispatch(b *testing.B) {
+	var n int
+	h := sneterr.SeverityHook(sneterr.SeverityDebug,
+		sneterr.SampledHook(func(sneterr.Error) { n++ }, 1))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h(sample)
+	}
+	sink = n
+}
+
+func benchCodeOf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		codeSink = sneterr.CodeOf(sample)
+	}
+}
+
+func benchCategoryOf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		categorySink = sneterr.CategoryOf(sample)
+	}
+}
+
+// RunAll runs every benchmark of the suite as a sub-benchmark of b.
+func RunAll(b *testing.B) {
+	for _, bm := range Benchmarks() {
+		b.Run(bm.Name, bm.F)
+	}
+}
+
+// A Result is the outcome of a benchmark run by Run.
+type Result struct {
+	Name        string        `json:"name"`
+	NsPerOp     time.Duration `json:"nsPerOp"`
+	AllocsPerOp int64         `json:"allocsPerOp"`
+	BytesPerOp  int64         `json:"bytesPerOp"`
+}
+
+// Run runs the benchmarks of the suite named in names, or all of them if
+// names is empty, outside of go test. The results can be stored to track
+// their evolution between releases.
+func Run(names ...string) []Result {
+	var rs []Result
+	for _, bm := range Benchmarks() {
+		if len(names) > 0 && !contains(names, bm.Name) {
+			continue
+		}
+		r := testing.Benchmark(bm.F)
+		rs = append(rs, Result{
+			Name:        bm.Name,
+			NsPerOp:     time.Duration(r.NsPerOp()),
+			AllocsPerOp: r.AllocsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+		})
+	}
+	return rs
+}
+
+// A Threshold is the maximum cost allowed for a benchmark. A zero field
+// is not checked; NoAllocs requires that the benchmark does not allocate.
+//
+// Time thresholds depend on the machine running the benchmark, and should
+// leave a margin for the noise of shared CI runners.
+type Threshold struct {
+	MaxNsPerOp     time.Duration
+	MaxAllocsPerOp int64
+	MaxBytesPerOp  int64
+	NoAllocs       bool
+}
+
+// Thresholds maps benchmark names to their threshold.
+type Thresholds map[string]Threshold
+
+// Exceeded returns a description of how r exceeds t, or an empty string if
+// it does not.
+func (t Threshold) Exceeded(r Result) string {
+	switch {
+	case t.MaxNsPerOp > 0 && r.NsPerOp > t.MaxNsPerOp:
+		return fmt.Sprintf("%s takes %v per op, want at most %v", r.Name, r.NsPerOp, t.MaxNsPerOp)
+	case t.NoAllocs && r.AllocsPerOp > 0:
+		return fmt.Sprintf("%s makes %d allocs per op, want none", r.Name, r.AllocsPerOp)
+	case t.MaxAllocsPerOp > 0 && r.AllocsPerOp > t.MaxAllocsPerOp:
+		return fmt.Sprintf("%s makes %d allocs per op, want at most %d", r.Name, r.AllocsPerOp, t.MaxAllocsPerOp)
+	case t.MaxBytesPerOp > 0 && r.BytesPerOp > t.MaxBytesPerOp:
+		return fmt.Sprintf("%s allocates %d bytes per op, want at most %d", r.Name, r.BytesPerOp, t.MaxBytesPerOp)
+	}
+	return ""
+}
+
+// Check runs the benchmarks named in thresholds and marks the test as
+// failed for each one exceeding its threshold. Benchmarks unknown to the
+// suite fail the test too, so that a renamed benchmark is not silently
+// left unchecked. Check skips the test in -short mode.
+func Check(t testing.TB, thresholds Thresholds) {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("skipping sneterr benchmarks in short mode")
+	}
+
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := Run(names...)
+	found := map[string]bool{}
+	for _, r := range results {
+		found[r.Name] = true
+		if msg := thresholds[r.Name].Exceeded(r); msg != "" {
+			t.Error(msg)
+		}
+	}
+	for _, name := range names {
+		if !found[name] {
+			t.Errorf("unknown sneterr benchmark %q", name)
+		}
+	}
+}
+
+// CheckAllocs marks the test as failed if CodeOf or CategoryOf allocate
+// when classifying errors. Services registering a Categorizer can call it
+// to check that theirs keeps CategoryOf free of allocations.
+func CheckAllocs(t testing.TB) {
+	t.Helper()
+
+	chains := []error{
+		sample,
+		sneterr.Join(cause, sample),
+		fmt.Errorf("query: %w", context.Canceled),
+		fmt.Errorf("query: %w", cause),
+	}
+	for _, err := range chains {
+		if n := testing.AllocsPerRun(100, func() { codeSink = sneterr.CodeOf(err) }); n > 0 {
+			t.Errorf("CodeOf(%v) makes %v allocs, want none", err, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { categorySink = sneterr.CategoryOf(err) }); n > 0 {
+			t.Errorf("CategoryOf(%v) makes %v allocs, want none", err, n)
+		}
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+// Package sneterrecho adapts the sneterrhttp middleware to the Echo web
+// framework.
+package sneterrecho
+
+import (
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"github.com/servicenetjp/sneterr/sneterrhttp"
+)
+
+// Middleware returns Echo middleware giving each request the warnings
+// collector and deprecation headers of m.
+func Middleware(m *sneterrhttp.Middleware) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(m.Wrap)
+}
+
+// ErrorHandler returns an echo.HTTPErrorHandler writing the error responses
+// with m. Errors raised by Echo itself, such as routing failures, are left
+// to Echo's default handler.
+//
+//	e.HTTPErrorHandler = sneterrecho.ErrorHandler(m)
+func ErrorHandler(m *sneterrhttp.Middleware) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			c.Echo().DefaultHTTPErrorHandler(err, c)
+			return
+		}
+		m.WriteError(c.Response(), c.Request(), err)
+	}
+}
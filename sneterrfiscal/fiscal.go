@@ -0,0 +1,152 @@
+// Package sneterrfiscal translates the numeric status codes of government
+// and fiscal APIs, such as the cStat of the SEFAZ web services for NF-e,
+// into coded errors. Each API registers a Table of its statuses once:
+//
+//	var nfse = sneterrfiscal.NewTable("nfse-sp", map[int]sneterrfiscal.Status{
+//		1:   {},
+//		206: {Code: "NFSeDuplicate", Message: "RPS já convertido em NFS-e."},
+//		999: {Code: "NFSeUnavailable", Retryable: true, Message: "Serviço indisponível."},
+//	})
+//
+//	return nfse.Error(resp.Codigo, resp.Mensagem)
+package sneterrfiscal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// ErrCodeUnknownStatus is the code of the errors for statuses missing from
+// their table.
+const ErrCodeUnknownStatus = "FiscalUnknownStatus"
+
+// Field keys of the errors returned by Table.Error.
+const (
+	// The name of the table of the API.
+	FieldAPI = "fiscal_api"
+
+	// The status returned by the API.
+	FieldStatus = "fiscal_status"
+
+	// The reason returned by the API with the status, such as the
+	// xMotivo of SEFAZ.
+	FieldReason = "fiscal_reason"
+)
+
+var schema = sneterr.Schema{
+	FieldAPI:    sneterr.FieldString,
+	FieldStatus: sneterr.FieldInt,
+	FieldReason: sneterr.FieldString,
+}
+
+func init() {
+	sneterr.Register(sneterr.CodeInfo{
+		Code:     ErrCodeUnknownStatus,
+		Category: sneterr.CategoryInternal,
+		Fields:   schema,
+		Status:   http.StatusBadGateway,
+	})
+}
+
+// A Status describes a status code of an API.
+type Status struct {
+	// The code of the errors for the status. A status without a code
+	// reports a success.
+	Code string
+
+	// The category of the code. CategoryUnavailable is assumed for
+	// retryable statuses and CategoryInvalidArgument for the others.
+	Category sneterr.Category
+
+	// Whether the request can be sent again as it is.
+	Retryable bool
+
+	// The message shown to the user, in Brazilian Portuguese. The reason
+	// returned by the API is used when empty.
+	Message string
+}
+
+// A Table translates the status codes of an API. It is safe for concurrent
+// use.
+type Table struct {
+	name     string
+	statuses map[int]Status
+}
+
+// NewTable returns the Table of the API name translating statuses, and
+// registers their codes in the sneterr catalog. It panics if statuses give
+// a code different retryability or categories, as the catalog holds them
+// per code, so it is meant to be called during package initialization.
+func NewTable(name string, statuses map[int]Status) *Table {
+	t := &Table{name: name, statuses: make(map[int]Status, len(statuses))}
+	infos := map[string]sneterr.CodeInfo{}
+	for status, s := range statuses {
+		if s.Code != "" && s.Category == "" {
+			s.Category = sneterr.CategoryInvalidArgument
+			if s.Retryable {
+				s.Category = sneterr.CategoryUnavailable
+			}
+		}
+		t.statuses[status] = s
+		if s.Code == "" {
+			continue
+		}
+
+		info := sneterr.CodeInfo{Code: s.Code, Category: s.Category, Fields: schema, Retryable: s.Retryable}
+		if prev, ok := infos[s.Code]; ok && (prev.Retryable != info.Retryable || prev.Category != info.Category) {
+			panic(fmt.Sprintf("sneterrfiscal: table %s gives code %s different retryability or categories", name, s.Code))
+		}
+		infos[s.Code] = info
+	}
+	for _, info := range infos {
+		sneterr.Register(info)
+	}
+	return t
+}
+
+// Name returns the name of the API of the table.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Lookup returns the description of status.
+func (t *Table) Lookup(status int) (Status, bool) {
+	s, ok := t.statuses[status]
+	return s, ok
+}
+
+// Error returns the error for status, returned by the API with reason, or
+// nil if status reports a success. The status and reason are preserved as
+// fields. Statuses missing from the table are reported with
+// ErrCodeUnknownStatus.
+func (t *Table) Error(status int, reason string) sneterr.Error {
+	sneterr.Helper()
+
+	s, ok := t.statuses[status]
+	switch {
+	case !ok:
+		s = Status{Code: ErrCodeUnknownStatus}
+	case s.Code == "":
+		return nil
+	}
+
+	msg := s.Message
+	if msg == "" {
+		msg = reason
+	}
+	if msg == "" {
+		msg = t.name + " status " + strconv.Itoa(status)
+	}
+	return sneterr.New(s.Code, msg,
+		sneterr.WithField(FieldAPI, t.name),
+		sneterr.WithField(FieldStatus, status),
+		sneterr.WithField(FieldReason, reason))
+}
+
+// StatusOf returns the status of the API preserved in the chain of err.
+func StatusOf(err error) (int, bool) {
+	return sneterr.Field[int](err, FieldStatus)
+}
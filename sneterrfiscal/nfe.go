@@ -0,0 +1,39 @@
+package sneterrfiscal
+
+import "github.com/servicenetjp/sneterr"
+
+// Codes of the errors of the NFe table.
+const (
+	ErrCodeNFeProcessing  = "NFeProcessing"
+	ErrCodeNFeUnavailable = "NFeUnavailable"
+	ErrCodeNFeThrottled   = "NFeThrottled"
+	ErrCodeNFeDenied      = "NFeDenied"
+	ErrCodeNFeDuplicate   = "NFeDuplicate"
+	ErrCodeNFeNotFound    = "NFeNotFound"
+	ErrCodeNFeInvalid     = "NFeInvalid"
+)
+
+// NFe is the table of the common statuses (cStat) of the SEFAZ web
+// services for NF-e. Integrations needing more statuses build their own
+// table.
+var NFe = NewTable("sefaz-nfe", map[int]Status{
+	100: {}, // Autorizado o uso da NF-e
+	101: {}, // Cancelamento de NF-e homologado
+	102: {}, // Inutilização de número homologado
+	103: {}, // Lote recebido com sucesso
+	104: {}, // Lote processado
+	107: {}, // Serviço em operação
+	135: {}, // Evento registrado e vinculado a NF-e
+	150: {}, // Autorizado o uso da NF-e, autorização fora de prazo
+
+	105: {Code: ErrCodeNFeProcessing, Retryable: true, Message: "O lote ainda está em processamento na SEFAZ."},
+	108: {Code: ErrCodeNFeUnavailable, Retryable: true, Message: "O serviço da SEFAZ está paralisado momentaneamente."},
+	109: {Code: ErrCodeNFeUnavailable, Retryable: true, Message: "O serviço da SEFAZ está paralisado sem previsão de retorno."},
+	656: {Code: ErrCodeNFeThrottled, Category: sneterr.CategoryResourceExhausted, Retryable: true, Message: "Consumo indevido do serviço da SEFAZ; aguarde antes de tentar novamente."},
+	110: {Code: ErrCodeNFeDenied, Category: sneterr.CategoryPermissionDenied, Message: "O uso da NF-e foi denegado pela SEFAZ."},
+	204: {Code: ErrCodeNFeDuplicate, Category: sneterr.CategoryConflict, Message: "Esta NF-e já foi enviada à SEFAZ."},
+	539: {Code: ErrCodeNFeDuplicate, Category: sneterr.CategoryConflict, Message: "Já existe uma NF-e com este número e uma chave de acesso diferente."},
+	217: {Code: ErrCodeNFeNotFound, Category: sneterr.CategoryNotFound, Message: "A NF-e não consta na base de dados da SEFAZ."},
+	215: {Code: ErrCodeNFeInvalid, Message: "O XML da NF-e não é válido."},
+	225: {Code: ErrCodeNFeInvalid, Message: "O XML da NF-e não é válido."},
+})
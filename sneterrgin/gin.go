@@ -0,0 +1,48 @@
+// Package sneterrgin adapts the sneterrhttp middleware to the Gin web
+// framework.
+package sneterrgin
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/servicenetjp/sneterr"
+	"github.com/servicenetjp/sneterr/sneterrhttp"
+)
+
+// Middleware returns Gin middleware writing the error response for the
+// last error added with gin.Context.Error, unless the handler already
+// wrote a response.
+//
+// Each request gets a sneterr.Warnings collector, whose warnings are logged
+// once the request is served, and is flagged for debugging with
+// sneterr.ContextWithDebug when m.Debug reports it. Gin gives no way to act before a handler
+// writes its header, so the deprecation headers are only set on error
+// responses.
+func Middleware(m *sneterrhttp.Middleware) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ws := sneterr.WarningsFromContext(ctx)
+		if ws == nil {
+			ctx, ws = sneterr.ContextWithWarnings(ctx)
+		}
+		if m.Debug(c.Request) && !sneterr.DebugFromContext(ctx) {
+			ctx = sneterr.ContextWithDebug(ctx)
+		}
+		if ctx != c.Request.Context() {
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+
+		if last := c.Errors.Last(); last != nil && !c.Writer.Written() {
+			m.WriteError(c.Writer, c.Request, last.Err)
+		}
+
+		logger := m.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		ws.Log(ctx, logger)
+	}
+}
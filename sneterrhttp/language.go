@@ -0,0 +1,43 @@
+package sneterrhttp
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// acceptLanguages returns the language tags of the Accept-Language header
+// of r, most preferred first.
+func acceptLanguages(r *http.Request) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+
+	var tags []tag
+	for _, h := range r.Header.Values("Accept-Language") {
+		for _, part := range strings.Split(h, ",") {
+			lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if lang == "" || lang == "*" {
+				continue
+			}
+			q := 1.0
+			if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+			if q > 0 {
+				tags = append(tags, tag{lang, q})
+			}
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	langs := make([]string, len(tags))
+	for i, t := range tags {
+		langs[i] = t.lang
+	}
+	return langs
+}
@@ -0,0 +1,21 @@
+package sneterrhttp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// leaderLocation returns the URL of the request r on leader, the address
+// of the leader reported by a sneterr.NewNotLeader error. An address
+// without scheme gets the scheme of r.
+func leaderLocation(r *http.Request, leader string) string {
+	leader = strings.TrimSuffix(leader, "/")
+	if !strings.Contains(leader, "://") {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		leader = scheme + "://" + leader
+	}
+	return leader + r.URL.RequestURI()
+}
@@ -0,0 +1,243 @@
+// Package sneterrhttp provides net/http middleware turning errors returned
+// by handlers into JSON error responses.
+package sneterrhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// A HandlerFunc is an HTTP handler which returns the error to report
+// instead of writing the error response itself.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Middleware writes the error responses of handlers and the deprecation
+// headers and warnings collected while serving requests. The zero
+// Middleware is ready to use.
+//
+// Requests whose baggage header has a sneterr.DebugBaggageKey member set,
+// or which have the DebugHeader header set to "1" or "true", are flagged
+// for debugging with sneterr.ContextWithDebug: the errors they fail with
+// are escalated with sneterr.Escalate and logged with their stack, which
+// is whole for errors created with sneterr.WithDebug.
+type Middleware struct {
+	// Logger receives failed requests and collected warnings.
+	// slog.Default() is used when nil.
+	Logger *slog.Logger
+
+	// DebugHeader is the name of a header flagging requests for
+	// debugging, in addition to the baggage. None when empty.
+	DebugHeader string
+}
+
+// Handler returns an http.Handler calling h with the default Middleware.
+func Handler(h HandlerFunc) http.Handler {
+	return (&Middleware{}).Handler(h)
+}
+
+// Handler returns an http.Handler calling h and writing the response for
+// the error it returns, if any.
+func (m *Middleware) Handler(h HandlerFunc) http.Handler {
+	return m.wrap(func(w *responseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			m.WriteError(w, r, err)
+		}
+	})
+}
+
+// Wrap returns an http.Handler calling next with a sneterr.Warnings
+// collector in the request context. The deprecation headers for the
+// collected warnings are set before the response header is written, and
+// the warnings are logged once next returns.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return m.wrap(func(w *responseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *Middleware) wrap(serve func(*responseWriter, *http.Request)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		ws := sneterr.WarningsFromContext(ctx)
+		if ws == nil {
+			ctx, ws = sneterr.ContextWithWarnings(ctx)
+		}
+		if m.Debug(r) && !sneterr.DebugFromContext(ctx) {
+			ctx = sneterr.ContextWithDebug(ctx)
+		}
+		if ctx != r.Context() {
+			r = r.WithContext(ctx)
+		}
+
+		serve(&responseWriter{ResponseWriter: w, warnings: ws}, r)
+		ws.Log(ctx, m.logger())
+	})
+}
+
+// WriteError logs err and writes the JSON error response for it to w.
+//
+// The status is chosen by sneterr.HTTPStatus. Responses with a 5xx status
+// only expose the error code and the status text, so internal details do
+// not leak to clients. The retry token of conflict errors is also set as
+// the ETag header, see RetryToken, and sneterr.NewNotLeader errors naming
+// the leader redirect the client to the same request on it with the
+// Location header. The Retry-After header is set for rate limited errors,
+// see sneterr.RetryAfter, while their limits are detailed in the body.
+// Nothing is written if the response was already started, but the error
+// is still logged.
+//
+// err is checked by sneterr.CheckBoundary before the response is written.
+func (m *Middleware) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	sneterr.CheckBoundary(err)
+	status := sneterr.HTTPStatus(err)
+	logged := sneterr.Escalate(r.Context(), err)
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.Any("error", logged),
+	}
+	if sneterr.Debug(logged) {
+		attrs = append(attrs, slog.String("stack", fmt.Sprintf("%+v", logged)))
+	}
+	m.logger().LogAttrs(r.Context(), sneterr.SeverityOf(err).Level(), "request failed", attrs...)
+
+	if written(w) {
+		return
+	}
+
+	var warnings []sneterr.Warning
+	if ws := sneterr.WarningsFromContext(r.Context()); ws != nil {
+		warnings = ws.List()
+	}
+
+	sneterr.SetDeprecationHeaders(w.Header(), warnings)
+	if token, ok := sneterr.RetryToken(err); ok && status < http.StatusInternalServerError {
+		w.Header().Set("ETag", quoteETag(token))
+	}
+	if d, ok := sneterr.RetryAfter(err); ok && status < http.StatusInternalServerError {
+		w.Header().Set("Retry-After", strconv.Itoa(int((d+time.Second-1)/time.Second)))
+	}
+	if leader, ok := sneterr.Leader(err); ok && status == http.StatusTemporaryRedirect {
+		w.Header().Set("Location", leaderLocation(r, leader))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(encodeBody(newResponseBody(err, status, warnings, acceptLanguages(r))))
+}
+
+// Debug reports whether r is flagged for debugging, by the DebugHeader
+// header or its baggage.
+func (m *Middleware) Debug(r *http.Request) bool {
+	if m.DebugHeader != "" {
+		if v := r.Header.Get(m.DebugHeader); v == "1" || strings.EqualFold(v, "true") {
+			return true
+		}
+	}
+	for _, baggage := range r.Header.Values("Baggage") {
+		if sneterr.DebugFromBaggage(baggage) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Middleware) logger() *slog.Logger {
+	if m.Logger != nil {
+		return m.Logger
+	}
+	return slog.Default()
+}
+
+// responseBody is the JSON body of an error response.
+type responseBody struct {
+	Code     string            `json:"code"`
+	Message  string            `json:"message"`
+	Fields   sneterr.Fields    `json:"fields,omitempty"`
+	Warnings []sneterr.Warning `json:"warnings,omitempty"`
+
+	MarshalErr string `json:"marshalError,omitempty"`
+}
+
+func newResponseBody(err error, status int, warnings []sneterr.Warning, langs []string) responseBody {
+	body := responseBody{
+		Code:     sneterr.ErrCodeUnknown,
+		Message:  http.StatusText(status),
+		Warnings: warnings,
+	}
+
+	var e sneterr.Error
+	if errors.As(err, &e) {
+		body.Code = e.Code()
+		if status < http.StatusInternalServerError {
+			body.Message = sneterr.Localize(err, langs...)
+			body.Fields = sneterr.FieldsOf(err)
+		}
+	}
+	return body
+}
+
+// encodeBody returns the JSON encoding of body. If its fields or warnings
+// cannot be encoded, such as cyclic values, they are left out and the
+// failure is noted in a "marshalError" member, so the response always has
+// a body.
+func encodeBody(body responseBody) []byte {
+	b, err := json.Marshal(body)
+	if err != nil {
+		body.Fields, body.Warnings = nil, nil
+		body.MarshalErr = err.Error()
+		b, _ = json.Marshal(body)
+	}
+	return append(b, '\n')
+}
+
+// written reports whether the response header was already written through
+// w or a responseWriter it wraps.
+func written(w http.ResponseWriter) bool {
+	for {
+		if rw, ok := w.(*responseWriter); ok {
+			return rw.wroteHeader
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
+
+// responseWriter sets the deprecation headers for the collected warnings
+// before the response header is written.
+type responseWriter struct {
+	http.ResponseWriter
+	warnings    *sneterr.Warnings
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		sneterr.SetDeprecationHeaders(w.Header(), w.warnings.List())
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,176 @@
+package sneterrhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// serve returns the response of m to a GET request for target failing
+// with err.
+func serve(t *testing.T, m *Middleware, target string, err error) *http.Response {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	w := httptest.NewRecorder()
+	m.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return err
+	}).ServeHTTP(w, r)
+	return w.Result()
+}
+
+func decodeBody(t *testing.T, resp *http.Response) responseBody {
+	t.Helper()
+	var body responseBody
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("cannot decode the response body: %v", err)
+	}
+	return body
+}
+
+func TestWriteErrorBody(t *testing.T) {
+	m := &Middleware{Logger: slog.New(slog.NewTextHandler(new(bytes.Buffer), nil))}
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+		fields  bool
+	}{
+		{
+			name:    "client error",
+			err:     sneterr.New("OrderNotFound", "order 7 was not found", sneterr.WithStatus(http.StatusNotFound), sneterr.WithField("order_id", 7)),
+			status:  http.StatusNotFound,
+			code:    "OrderNotFound",
+			message: "order 7 was not found",
+			fields:  true,
+		},
+		{
+			name:    "server error",
+			err:     sneterr.New("DatabaseDown", "cannot reach db.internal:5432", sneterr.WithStatus(http.StatusBadGateway), sneterr.WithField("dsn", "postgres://db.internal")),
+			status:  http.StatusBadGateway,
+			code:    "DatabaseDown",
+			message: http.StatusText(http.StatusBadGateway),
+		},
+		{
+			name:    "plain error",
+			err:     errors.New("connection reset"),
+			status:  http.StatusInternalServerError,
+			code:    sneterr.ErrCodeUnknown,
+			message: http.StatusText(http.StatusInternalServerError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serve(t, m, "/orders/7", tt.err)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			body := decodeBody(t, resp)
+			if body.Code != tt.code || body.Message != tt.message {
+				t.Errorf("body = %s: %s, want %s: %s", body.Code, body.Message, tt.code, tt.message)
+			}
+			if (len(body.Fields) > 0) != tt.fields {
+				t.Errorf("body fields = %v, want fields %v", body.Fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestWriteErrorHeaders(t *testing.T) {
+	m := &Middleware{Logger: slog.New(slog.NewTextHandler(new(bytes.Buffer), nil))}
+	limited := sneterr.NewRateLimited([]sneterr.Limit{{
+		Scope:    sneterr.LimitScopeTenant,
+		Key:      "acme",
+		Limit:    10,
+		Window:   time.Minute,
+		Reset:    time.Now().Add(10 * time.Second),
+		Exceeded: true,
+	}})
+
+	tests := []struct {
+		name   string
+		err    error
+		header string
+		want   string
+	}{
+		{
+			name:   "retry token",
+			err:    sneterr.NewConflict("OrderChanged", "order was changed", 1, 2, sneterr.WithRetryToken("v2")),
+			header: "ETag",
+			want:   `"v2"`,
+		},
+		{name: "rate limited", err: limited, header: "Retry-After", want: "10"},
+		{
+			name:   "not leader",
+			err:    sneterr.NewNotLeader("10.0.0.2:8443"),
+			header: "Location",
+			want:   "http://10.0.0.2:8443/orders?id=7",
+		},
+		{
+			name:   "unknown leader",
+			err:    sneterr.NewNotLeader(""),
+			header: "Location",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serve(t, m, "/orders?id=7", tt.err)
+			if got := resp.Header.Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareDebug(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		debug  bool
+	}{
+		{name: "none"},
+		{name: "header", header: http.Header{"X-Debug": {"true"}}, debug: true},
+		{name: "header off", header: http.Header{"X-Debug": {"0"}}},
+		{name: "baggage", header: http.Header{"Baggage": {"user=7, " + sneterr.DebugBaggageKey + "=1"}}, debug: true},
+		{name: "other baggage", header: http.Header{"Baggage": {"user=7"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			m := &Middleware{
+				Logger:      slog.New(slog.NewTextHandler(&logs, nil)),
+				DebugHeader: "X-Debug",
+			}
+			r := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+			for k, vs := range tt.header {
+				r.Header[k] = vs
+			}
+
+			var flagged bool
+			m.Handler(func(w http.ResponseWriter, r *http.Request) error {
+				flagged = sneterr.DebugFromContext(r.Context())
+				return sneterr.New("OrderNotFound", "order was not found", sneterr.WithStatus(http.StatusNotFound))
+			}).ServeHTTP(httptest.NewRecorder(), r)
+
+			if flagged != tt.debug {
+				t.Errorf("DebugFromContext() = %v, want %v", flagged, tt.debug)
+			}
+			if logged := strings.Contains(logs.String(), "stack="); logged != tt.debug {
+				t.Errorf("stack logged = %v, want %v in %s", logged, tt.debug, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package sneterrhttp
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RetryToken returns the retry token a client echoes back when retrying a
+// request which failed with a conflict, from the If-Match header, as
+// exposed by WriteError from sneterr.WithRetryToken. It reports false if
+// the request has no single token.
+func RetryToken(r *http.Request) (string, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" || strings.Contains(v, ",") {
+		return "", false
+	}
+	v = strings.TrimPrefix(v, "W/")
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		v = v[1 : len(v)-1]
+	}
+	return v, v != ""
+}
+
+// quoteETag returns token as an entity tag, quoting it unless it already
+// is.
+func quoteETag(token string) string {
+	if strings.HasPrefix(token, `"`) || strings.HasPrefix(token, `W/"`) {
+		return token
+	}
+	return `"` + token + `"`
+}
@@ -0,0 +1,98 @@
+package sneterrhttp
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// roundTrip returns err as received by a client: written by WriteError
+// and decoded by sneterr.FromResponse.
+func roundTrip(t *testing.T, err error) sneterr.RequestFailure {
+	t.Helper()
+	m := &Middleware{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	w := httptest.NewRecorder()
+	m.WriteError(w, httptest.NewRequest(http.MethodGet, "/", nil), err)
+	return sneterr.FromResponse(w.Result())
+}
+
+// TestRoundTripDetails checks that the details attached to errors by their
+// constructors are read back from the error, from an error wrapping it and
+// from the error received by a client.
+func TestRoundTripDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+
+		// detail reads the detail of err.
+		detail func(err error) (interface{}, bool)
+
+		// The detail read, nil if none must be found.
+		want interface{}
+	}{
+		{
+			name: "transition",
+			err:  sneterr.NewInvalidTransition("order", "shipped", "cancel", []string{"deliver", "return"}),
+			detail: func(err error) (interface{}, bool) {
+				return sneterr.InvalidTransitionOf(err)
+			},
+			want: sneterr.Transition{Entity: "order", State: "shipped", Event: "cancel", Allowed: []string{"deliver", "return"}},
+		},
+		{
+			name: "leader",
+			err:  sneterr.NewNotLeader("10.0.0.2:8443"),
+			detail: func(err error) (interface{}, bool) {
+				return sneterr.Leader(err)
+			},
+			want: "10.0.0.2:8443",
+		},
+		{
+			name: "election",
+			err:  sneterr.NewNotLeader(""),
+			detail: func(err error) (interface{}, bool) {
+				return sneterr.Leader(err)
+			},
+		},
+		{
+			name: "version skew",
+			err:  sneterr.NewIncompatibleVersion("schema", "v3", "v2"),
+			detail: func(err error) (interface{}, bool) {
+				return sneterr.IncompatibleVersionOf(err)
+			},
+			want: sneterr.VersionSkew{Component: "schema", Peer: "v3", Local: "v2"},
+		},
+		{
+			name: "entitlement",
+			err:  sneterr.NewEntitlementError(sneterr.Entitlement{Name: "sso", Plan: "enterprise", UpgradeURL: "https://example.com/upgrade"}),
+			detail: func(err error) (interface{}, bool) {
+				return sneterr.EntitlementOf(err)
+			},
+			want: sneterr.Entitlement{Name: "sso", Plan: "enterprise", UpgradeURL: "https://example.com/upgrade"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, err := range map[string]error{
+				"local":   tt.err,
+				"wrapped": sneterr.Wrap(tt.err, "Wrapped", "operation failed"),
+				"decoded": roundTrip(t, tt.err),
+			} {
+				got, ok := tt.detail(err)
+				if tt.want == nil {
+					if ok {
+						t.Errorf("%s: detail %+v found, want none", name, got)
+					}
+					continue
+				}
+				if !ok || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: detail = %+v, %v, want %+v, true", name, got, ok, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package sneterrimport collects the errors found while importing a file,
+// such as a CSV or spreadsheet upload, and reports them both as a
+// sneterr batch error for API clients and as a CSV file for the people
+// who have to fix the file.
+//
+//	report := sneterrimport.NewReport(header)
+//	for {
+//		record, err := r.Read()
+//		...
+//		row := report.Row(record)
+//		if _, err := mail.ParseAddress(record[1]); err != nil {
+//			report.Fail(row, "email", "InvalidEmail", "invalid e-mail address")
+//		}
+//	}
+//	if err := report.Err(); err != nil {
+//		return err
+//	}
+package sneterrimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// ErrorColumn is the name of the column added to the CSV report.
+const ErrorColumn = "error"
+
+// A Report collects the rows of an imported file and the errors found in
+// them. It is safe for concurrent use.
+type Report struct {
+	mu     sync.Mutex
+	header []string
+	rows   [][]string
+	errs   []error
+	byRow  map[int][]string
+}
+
+// NewReport returns an empty Report for a file with header.
+func NewReport(header []string) *Report {
+	return &Report{header: header, byRow: map[int][]string{}}
+}
+
+// Row records the next row of the file and returns its number, counting
+// from 1 after the header.
+func (r *Report) Row(record []string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rows = append(r.rows, record)
+	return len(r.rows)
+}
+
+// Fail records an error with code and message for column of row. column
+// is empty when the whole row is at fault.
+func (r *Report) Fail(row int, column, code, message string) {
+	sneterr.Helper()
+	r.add(row, column, sneterr.NewFieldError(row, column, code, message))
+}
+
+// FailErr records err as an error for column of row, keeping its code and
+// message.
+func (r *Report) FailErr(row int, column string, err error) {
+	sneterr.Helper()
+
+	msg := err.Error()
+	if e, ok := err.(sneterr.Error); ok {
+		msg = e.Message()
+	}
+	r.add(row, column, sneterr.NewFieldError(row, column, sneterr.CodeOf(err), msg, sneterr.WithCause(err)))
+}
+
+func (r *Report) add(row int, column string, err sneterr.Error) {
+	text := err.Message()
+	if column != "" {
+		text = column + ": " + text
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+	r.byRow[row] = append(r.byRow[row], text)
+}
+
+// Rows returns the number of rows recorded.
+func (r *Report) Rows() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rows)
+}
+
+// FailedRows returns the numbers of the rows with errors, in increasing
+// order.
+func (r *Report) FailedRows() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rows := make([]int, 0, len(r.byRow))
+	for row := range r.byRow {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Err returns a batch error grouping the errors recorded, ordered by row,
+// or nil if there are none.
+func (r *Report) Err() sneterr.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+	errs := append([]error(nil), r.errs...)
+	sort.SliceStable(errs, func(i, j int) bool {
+		ri, _, _ := sneterr.Position(errs[i])
+		rj, _, _ := sneterr.Position(errs[j])
+		return ri < rj
+	})
+	msg := fmt.Sprintf("%d of %d rows have errors", len(r.byRow), len(r.rows))
+	return sneterr.NewBatchError(msg, errs)
+}
+
+// WriteCSV writes the report as CSV to w: the header and the rows of the
+// file, with an ErrorColumn column holding the errors of each row. Only
+// the rows with errors are written if failedOnly is true, so the file can
+// be fixed and imported again.
+func (r *Report) WriteCSV(w io.Writer, failedOnly bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cw := csv.NewWriter(w)
+	if r.header != nil {
+		if err := cw.Write(append(append([]string(nil), r.header...), ErrorColumn)); err != nil {
+			return err
+		}
+	}
+	for i, record := range r.rows {
+		errs, failed := r.byRow[i+1]
+		if failedOnly && !failed {
+			continue
+		}
+		line := append(append([]string(nil), record...), strings.Join(errs, "; "))
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,160 @@
+// Package sneterrldap translates the errors of LDAP directories, as
+// returned by github.com/go-ldap/ldap, and SASL authentication failures
+// into codes of the Auth namespace.
+//
+// The messages of the errors are safe to show to clients: the diagnostic
+// messages of the servers are only kept in the original error, for the
+// logs. In particular an unknown user is reported as invalid credentials,
+// so clients cannot probe which accounts exist.
+package sneterrldap
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/servicenetjp/sneterr"
+)
+
+// Codes of the errors returned by the converters.
+const (
+	ErrCodeInvalidCredentials   = "AuthInvalidCredentials"
+	ErrCodeAccountLocked        = "AuthAccountLocked"
+	ErrCodeAccountDisabled      = "AuthAccountDisabled"
+	ErrCodePasswordExpired      = "AuthPasswordExpired"
+	ErrCodePermissionDenied     = "AuthPermissionDenied"
+	ErrCodeMechanismUnsupported = "AuthMechanismUnsupported"
+	ErrCodeServerDown           = "AuthServerDown"
+	ErrCodeTemporaryFailure     = "AuthTemporaryFailure"
+	ErrCodeAuthFailed           = "AuthFailed"
+)
+
+// Field keys of the errors returned by the converters.
+const (
+	FieldResultCode    = "ldap_result_code"
+	FieldSASLMechanism = "sasl_mechanism"
+	FieldSASLCondition = "sasl_condition"
+)
+
+func init() {
+	schema := sneterr.Schema{
+		FieldResultCode:    sneterr.FieldInt,
+		FieldSASLMechanism: sneterr.FieldString,
+		FieldSASLCondition: sneterr.FieldString,
+	}
+	for _, info := range []sneterr.CodeInfo{
+		{Code: ErrCodeInvalidCredentials, Category: sneterr.CategoryUnauthenticated, Severity: sneterr.SeverityInfo},
+		{Code: ErrCodeAccountLocked, Category: sneterr.CategoryPermissionDenied, Severity: sneterr.SeverityWarn},
+		{Code: ErrCodeAccountDisabled, Category: sneterr.CategoryPermissionDenied, Severity: sneterr.SeverityInfo},
+		{Code: ErrCodePasswordExpired, Category: sneterr.CategoryUnauthenticated, Severity: sneterr.SeverityInfo},
+		{Code: ErrCodePermissionDenied, Category: sneterr.CategoryPermissionDenied, Severity: sneterr.SeverityInfo},
+		{Code: ErrCodeMechanismUnsupported, Category: sneterr.CategoryInternal, Status: http.StatusBadGateway},
+		{Code: ErrCodeServerDown, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeTemporaryFailure, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeAuthFailed, Category: sneterr.CategoryInternal, Status: http.StatusBadGateway},
+	} {
+		info.Fields = schema
+		sneterr.Register(info)
+	}
+}
+
+// messages are the external messages of the errors.
+var messages = map[string]string{
+	ErrCodeInvalidCredentials:   "invalid credentials",
+	ErrCodeAccountLocked:        "the account is locked",
+	ErrCodeAccountDisabled:      "the account is disabled",
+	ErrCodePasswordExpired:      "the password has expired and must be changed",
+	ErrCodePermissionDenied:     "insufficient access rights",
+	ErrCodeMechanismUnsupported: "the authentication mechanism is not supported",
+	ErrCodeServerDown:           "the directory server is unavailable",
+	ErrCodeTemporaryFailure:     "authentication failed temporarily",
+	ErrCodeAuthFailed:           "authentication failed",
+}
+
+// resultCodes maps LDAP result codes to error codes.
+var resultCodes = map[uint16]string{
+	ldap.LDAPResultInvalidCredentials:          ErrCodeInvalidCredentials,
+	ldap.LDAPResultInappropriateAuthentication: ErrCodeInvalidCredentials,
+	ldap.LDAPResultInsufficientAccessRights:    ErrCodePermissionDenied,
+	ldap.LDAPResultAuthMethodNotSupported:      ErrCodeMechanismUnsupported,
+	ldap.LDAPResultStrongAuthRequired:          ErrCodeMechanismUnsupported,
+	ldap.LDAPResultConfidentialityRequired:     ErrCodeMechanismUnsupported,
+	ldap.LDAPResultBusy:                        ErrCodeTemporaryFailure,
+	ldap.LDAPResultTimeLimitExceeded:           ErrCodeTemporaryFailure,
+	ldap.LDAPResultUnavailable:                 ErrCodeServerDown,
+	ldap.ErrorNetwork:                          ErrCodeServerDown,
+}
+
+// adDataCodes maps the "data" codes which Active Directory appends to the
+// diagnostic message of invalid credentials to error codes.
+var adDataCodes = map[string]string{
+	"525": ErrCodeInvalidCredentials, // user not found
+	"52e": ErrCodeInvalidCredentials, // invalid credentials
+	"530": ErrCodePermissionDenied,   // not permitted to log on at this time
+	"531": ErrCodePermissionDenied,   // not permitted to log on from this workstation
+	"532": ErrCodePasswordExpired,    // password expired
+	"533": ErrCodeAccountDisabled,    // account disabled
+	"701": ErrCodeAccountDisabled,    // account expired
+	"773": ErrCodePasswordExpired,    // password must be reset
+	"775": ErrCodeAccountLocked,      // account locked out
+}
+
+// FromError translates err when it has an *ldap.Error in its chain, such
+// as the errors returned by Conn.Bind. Invalid credentials are refined
+// with the diagnostic of Active Directory and password policy servers, to
+// tell locked, disabled and expired accounts apart.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil FromError
+// returns nil.
+func FromError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var ldapErr *ldap.Error
+	if !errors.As(err, &ldapErr) {
+		if e, ok := err.(sneterr.Error); ok {
+			return e
+		}
+		return sneterr.Wrap(err, sneterr.ErrCodeUnknown, err.Error())
+	}
+
+	code, ok := resultCodes[ldapErr.ResultCode]
+	if !ok {
+		code = ErrCodeAuthFailed
+	}
+	if ldapErr.ResultCode == ldap.LDAPResultInvalidCredentials && ldapErr.Err != nil {
+		if c, ok := diagnosticCode(ldapErr.Err.Error()); ok {
+			code = c
+		}
+	}
+	return sneterr.New(code, messages[code],
+		sneterr.WithCause(err),
+		sneterr.WithField(FieldResultCode, int(ldapErr.ResultCode)))
+}
+
+// diagnosticCode returns the code of the error described by the
+// diagnostic message of a server.
+func diagnosticCode(diag string) (string, bool) {
+	if _, after, ok := strings.Cut(diag, "data "); ok {
+		data, _, _ := strings.Cut(after, ",")
+		if code, ok := adDataCodes[strings.ToLower(strings.TrimSpace(data))]; ok {
+			return code, true
+		}
+	}
+
+	// Messages of the password policy overlay of OpenLDAP and 389.
+	diag = strings.ToLower(diag)
+	switch {
+	case strings.Contains(diag, "account locked"), strings.Contains(diag, "account is locked"):
+		return ErrCodeAccountLocked, true
+	case strings.Contains(diag, "password expired"), strings.Contains(diag, "password has expired"):
+		return ErrCodePasswordExpired, true
+	case strings.Contains(diag, "account disabled"), strings.Contains(diag, "account inactivated"):
+		return ErrCodeAccountDisabled, true
+	}
+	return "", false
+}
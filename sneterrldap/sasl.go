@@ -0,0 +1,41 @@
+package sneterrldap
+
+import (
+	"strings"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// saslConditions maps the SASL failure conditions of RFC 6120 to error
+// codes.
+var saslConditions = map[string]string{
+	"not-authorized":         ErrCodeInvalidCredentials,
+	"invalid-authzid":        ErrCodePermissionDenied,
+	"account-disabled":       ErrCodeAccountDisabled,
+	"credentials-expired":    ErrCodePasswordExpired,
+	"invalid-mechanism":      ErrCodeMechanismUnsupported,
+	"mechanism-too-weak":     ErrCodeMechanismUnsupported,
+	"encryption-required":    ErrCodeMechanismUnsupported,
+	"temporary-auth-failure": ErrCodeTemporaryFailure,
+	"aborted":                ErrCodeAuthFailed,
+	"incorrect-encoding":     ErrCodeAuthFailed,
+	"malformed-request":      ErrCodeAuthFailed,
+}
+
+// FromSASLFailure translates the SASL failure condition reported by a
+// server during an authentication with mechanism, such as "PLAIN" or
+// "SCRAM-SHA-256", into an error wrapping cause. Unknown conditions are
+// reported with ErrCodeAuthFailed.
+func FromSASLFailure(mechanism, condition string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	condition = strings.ToLower(strings.TrimSpace(condition))
+	code, ok := saslConditions[condition]
+	if !ok {
+		code = ErrCodeAuthFailed
+	}
+	return sneterr.New(code, messages[code],
+		sneterr.WithCause(cause),
+		sneterr.WithField(FieldSASLMechanism, strings.ToUpper(mechanism)),
+		sneterr.WithField(FieldSASLCondition, condition))
+}
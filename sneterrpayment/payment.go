@@ -0,0 +1,184 @@
+// Package sneterrpayment translates the decline and reason codes of
+// payment providers into the codes of the payment namespace, with
+// messages safe to show to the payer and hints on how to remediate.
+//
+// Each provider integration registers the table of its own codes once:
+//
+//	func init() {
+//		sneterrpayment.RegisterProvider("acme", map[string]string{
+//			"51": sneterrpayment.ErrCodeInsufficientFunds,
+//			"54": sneterrpayment.ErrCodeExpiredCard,
+//			"43": sneterrpayment.ErrCodeCardDeclined, // stolen card
+//		})
+//	}
+//
+// and reports declines with FromDecline:
+//
+//	return sneterrpayment.FromDecline("acme", resp.ResponseCode, nil)
+//
+// The messages are declared as templates of their code, so they can be
+// translated with sneterr.LocalizedTemplate.
+package sneterrpayment
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// Codes of the payment namespace.
+const (
+	ErrCodeCardDeclined           = "payment.CardDeclined"
+	ErrCodeInsufficientFunds      = "payment.InsufficientFunds"
+	ErrCodeExpiredCard            = "payment.ExpiredCard"
+	ErrCodeIncorrectCVC           = "payment.IncorrectCVC"
+	ErrCodeIncorrectNumber        = "payment.IncorrectNumber"
+	ErrCodeCardNotSupported       = "payment.CardNotSupported"
+	ErrCodeLimitExceeded          = "payment.LimitExceeded"
+	ErrCodeAuthenticationRequired = "payment.AuthenticationRequired"
+	ErrCodeDuplicateTransaction   = "payment.DuplicateTransaction"
+	ErrCodeProcessingError        = "payment.ProcessingError"
+)
+
+// FieldRemediation is the field key of the remediation hint of errors.
+const FieldRemediation = "remediation"
+
+// A Remediation hints at what the payer can do after a decline.
+type Remediation string
+
+// Remediations of the payment codes.
+const (
+	RemediationNone           Remediation = "none"
+	RemediationRetry          Remediation = "retry"
+	RemediationRetryLater     Remediation = "retry_later"
+	RemediationUpdateCard     Remediation = "update_card"
+	RemediationUseAnotherCard Remediation = "use_another_card"
+	RemediationContactIssuer  Remediation = "contact_issuer"
+	RemediationAuthenticate   Remediation = "authenticate"
+)
+
+// A Reason describes a code of the payment namespace.
+type Reason struct {
+	// The code, starting with "payment.".
+	Code string
+
+	// The message shown to the payer. It must not reveal why the issuer
+	// declined when that could help fraud, such as a card reported
+	// stolen.
+	Message string
+
+	// What the payer can do about it.
+	Remediation Remediation
+
+	// Whether the payment can be attempted again as it is.
+	Retryable bool
+}
+
+var (
+	mu        sync.RWMutex
+	reasons   = map[string]Reason{}
+	providers = map[string]map[string]string{}
+)
+
+func init() {
+	for _, r := range []Reason{
+		{ErrCodeCardDeclined, "Your card was declined.", RemediationUseAnotherCard, false},
+		{ErrCodeInsufficientFunds, "Your card has insufficient funds.", RemediationUseAnotherCard, false},
+		{ErrCodeExpiredCard, "Your card has expired.", RemediationUpdateCard, false},
+		{ErrCodeIncorrectCVC, "Your card's security code is incorrect.", RemediationUpdateCard, false},
+		{ErrCodeIncorrectNumber, "Your card number is incorrect.", RemediationUpdateCard, false},
+		{ErrCodeCardNotSupported, "Your card does not support this type of purchase.", RemediationUseAnotherCard, false},
+		{ErrCodeLimitExceeded, "Your card has exceeded its limit.", RemediationContactIssuer, false},
+		{ErrCodeAuthenticationRequired, "Your bank requires you to authenticate this payment.", RemediationAuthenticate, false},
+		{ErrCodeDuplicateTransaction, "An identical payment was just made.", RemediationNone, false},
+		{ErrCodeProcessingError, "Your payment could not be processed. Please try again.", RemediationRetryLater, true},
+	} {
+		Define(r)
+	}
+}
+
+// Define adds r to the codes of the payment namespace, replacing any
+// previous definition of its code. It registers the code in the sneterr
+// catalog, with HTTP status 402 Payment Required so that the message is
+// shown to the payer even for failures of the provider, and its message
+// as the template of the code.
+func Define(r Reason) {
+	info := sneterr.CodeInfo{
+		Code:      r.Code,
+		Severity:  sneterr.SeverityInfo,
+		Fields:    sneterr.Schema{FieldRemediation: sneterr.FieldString},
+		Retryable: r.Retryable,
+		Status:    http.StatusPaymentRequired,
+	}
+	if r.Retryable {
+		info.Category = sneterr.CategoryUnavailable
+		info.Severity = sneterr.SeverityWarn
+	}
+	sneterr.Register(info)
+	sneterr.Template(r.Code, r.Message)
+
+	mu.Lock()
+	reasons[r.Code] = r
+	mu.Unlock()
+}
+
+// RegisterProvider registers the table translating the decline codes of
+// provider into codes of the payment namespace, replacing any previous
+// table of provider. It panics if a code of the table is not defined, so
+// it is meant to be called during package initialization.
+func RegisterProvider(provider string, codes map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	table := make(map[string]string, len(codes))
+	for reason, code := range codes {
+		if _, ok := reasons[code]; !ok {
+			panic(fmt.Sprintf("sneterrpayment: provider %s maps %q to undefined code %q", provider, reason, code))
+		}
+		table[reason] = code
+	}
+	providers[provider] = table
+}
+
+// FromDecline returns the error for the decline code reason of provider.
+// Codes missing from the table of the provider are reported as
+// ErrCodeCardDeclined.
+//
+// The error only exposes its code, message and remediation: provider and
+// reason are kept in its original error, which is cause if not nil, for
+// the logs.
+func FromDecline(provider, reason string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	mu.RLock()
+	code, ok := providers[provider][reason]
+	if !ok {
+		code = ErrCodeCardDeclined
+	}
+	r := reasons[code]
+	mu.RUnlock()
+
+	if cause == nil {
+		cause = fmt.Errorf("%s declined the payment with reason %q", provider, reason)
+	}
+	return sneterr.NewT(code, nil,
+		sneterr.WithCause(cause),
+		sneterr.WithField(FieldRemediation, string(r.Remediation)))
+}
+
+// RemediationOf returns the remediation hint of the first payment error in
+// the chain of err.
+func RemediationOf(err error) (Remediation, bool) {
+	s, ok := sneterr.Field[string](err, FieldRemediation)
+	return Remediation(s), ok
+}
+
+// Lookup returns the definition of the payment code.
+func Lookup(code string) (Reason, bool) {
+	mu.RLock()
+	r, ok := reasons[code]
+	mu.RUnlock()
+	return r, ok
+}
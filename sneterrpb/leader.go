@@ -0,0 +1,59 @@
+package sneterrpb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/servicenetjp/sneterr"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// NotLeaderTypeURL is the type URL of the sneterr.v1.NotLeader message in
+// a google.protobuf.Any.
+const NotLeaderTypeURL = "type.googleapis.com/sneterr.v1.NotLeader"
+
+// ToNotLeaderProto returns the leader reported by a sneterr.NewNotLeader
+// error in the chain of err, encoded as a sneterr.v1.NotLeader message in
+// a google.protobuf.Any, to attach to a gRPC status next to the message
+// returned by ToProto. It reports false if err does not name a leader.
+func ToNotLeaderProto(err error) (*anypb.Any, bool) {
+	leader, ok := sneterr.Leader(err)
+	if !ok {
+		return nil, false
+	}
+	return &anypb.Any{TypeUrl: NotLeaderTypeURL, Value: appendString(nil, 1, leader)}, true
+}
+
+// FromNotLeaderProto returns the address of the leader encoded in a,
+// which must hold a sneterr.v1.NotLeader message.
+func FromNotLeaderProto(a *anypb.Any) (string, error) {
+	if a.GetTypeUrl() != NotLeaderTypeURL {
+		return "", fmt.Errorf("sneterrpb: unexpected type %q", a.GetTypeUrl())
+	}
+
+	var leader string
+	b := a.GetValue()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if num == 1 && typ == protowire.BytesType {
+			var v []byte
+			v, n = protowire.ConsumeBytes(b)
+			leader = string(v)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return "", fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	if leader == "" {
+		return "", errors.New("sneterrpb: NotLeader message without leader")
+	}
+	return leader, nil
+}
@@ -0,0 +1,255 @@
+// Package sneterrpb encodes sneterr errors as the sneterr.v1.Error protocol
+// buffers message described in error.proto, so they can travel between
+// services, for instance as a google.protobuf.Any detail of a gRPC status
+// or in the payload of an event, without being flattened to a string.
+// Warnings of successful gRPC calls travel as sneterr.v1.Warning messages
+// in their trailers, see AppendWarnings.
+//
+// The message is encoded directly with protowire, so the package does not
+// need generated code.
+package sneterrpb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/servicenetjp/sneterr"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// TypeURL is the type URL of the sneterr.v1.Error message in a
+// google.protobuf.Any.
+const TypeURL = "type.googleapis.com/sneterr.v1.Error"
+
+// Field numbers of the sneterr.v1.Error message.
+const (
+	fieldCode        protowire.Number = 1
+	fieldMessage     protowire.Number = 2
+	fieldMetadata    protowire.Number = 3
+	fieldCause       protowire.Number = 4
+	fieldFingerprint protowire.Number = 5
+	fieldErrors      protowire.Number = 6
+)
+
+// maxDepth bounds the nesting of causes accepted by Unmarshal.
+const maxDepth = 100
+
+// ToProto returns err encoded as a sneterr.v1.Error message in a
+// google.protobuf.Any. err is checked by sneterr.CheckBoundary first.
+func ToProto(err error) (*anypb.Any, error) {
+	sneterr.CheckBoundary(err)
+	b, mErr := Marshal(err)
+	if mErr != nil {
+		return nil, mErr
+	}
+	return &anypb.Any{TypeUrl: TypeURL, Value: b}, nil
+}
+
+// FromProto rebuilds the error encoded in a, which must hold a
+// sneterr.v1.Error message.
+func FromProto(a *anypb.Any) (sneterr.Error, error) {
+	if a.GetTypeUrl() != TypeURL {
+		return nil, fmt.Errorf("sneterrpb: unexpected type %q", a.GetTypeUrl())
+	}
+	return Unmarshal(a.GetValue())
+}
+
+// Marshal returns the wire encoding of err as a sneterr.v1.Error message.
+// Errors which are not a sneterr.Error are encoded with their message
+// only.
+func Marshal(err error) ([]byte, error) {
+	if err == nil {
+		return nil, errors.New("sneterrpb: cannot marshal a nil error")
+	}
+	return appendError(nil, err, sneterr.Fingerprint(err))
+}
+
+func appendError(b []byte, err error, fingerprint string) ([]byte, error) {
+	e, ok := err.(sneterr.Error)
+	if !ok {
+		return appendString(b, fieldMessage, err.Error()), nil
+	}
+
+	b = appendString(b, fieldCode, e.Code())
+	b = appendString(b, fieldMessage, e.Message())
+	b = appendString(b, fieldFingerprint, fingerprint)
+
+	if f, ok := e.(interface{ Fields() sneterr.Fields }); ok {
+		var fErr error
+		if b, fErr = appendFields(b, f.Fields()); fErr != nil {
+			return nil, fErr
+		}
+	}
+
+	if m, ok := e.(sneterr.MultiError); ok {
+		for _, err := range sneterr.OrderedErrors(m) {
+			var mErr error
+			if b, mErr = appendMessage(b, fieldErrors, err); mErr != nil {
+				return nil, mErr
+			}
+		}
+		return b, nil
+	}
+
+	if cause := e.OrigErr(); cause != nil {
+		return appendMessage(b, fieldCause, cause)
+	}
+	return b, nil
+}
+
+// appendFields appends fields as metadata map entries, sorted by key so
+// the encoding is deterministic.
+func appendFields(b []byte, fields sneterr.Fields) ([]byte, error) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, err := json.Marshal(fields[k])
+		if err != nil {
+			return nil, fmt.Errorf("sneterrpb: encode field %q: %w", k, err)
+		}
+		var entry []byte
+		entry = appendString(entry, 1, k)
+		entry = appendString(entry, 2, string(v))
+		b = protowire.AppendTag(b, fieldMetadata, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b, nil
+}
+
+func appendMessage(b []byte, num protowire.Number, err error) ([]byte, error) {
+	msg, mErr := appendError(nil, err, "")
+	if mErr != nil {
+		return nil, mErr
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg), nil
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// Unmarshal rebuilds the error from the wire encoding of a
+// sneterr.v1.Error message.
+func Unmarshal(b []byte) (sneterr.Error, error) {
+	m, err := decode(b, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("sneterrpb: %w", err)
+	}
+	return sneterr.UnmarshalError(data)
+}
+
+// message is a decoded sneterr.v1.Error, in the shape of the JSON encoding
+// of sneterr errors.
+type message struct {
+	Code        string                     `json:"code,omitempty"`
+	Message     string                     `json:"message"`
+	Fields      map[string]json.RawMessage `json:"fields,omitempty"`
+	Fingerprint string                     `json:"fingerprint,omitempty"`
+	Errors      []*message                 `json:"errors,omitempty"`
+	Cause       *message                   `json:"cause,omitempty"`
+}
+
+func decode(b []byte, depth int) (*message, error) {
+	if depth > maxDepth {
+		return nil, errors.New("sneterrpb: error nested too deeply")
+	}
+
+	m := &message{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return nil, fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		switch num {
+		case fieldCode:
+			m.Code = string(v)
+		case fieldMessage:
+			m.Message = string(v)
+		case fieldFingerprint:
+			m.Fingerprint = string(v)
+		case fieldMetadata:
+			k, val, err := decodeEntry(v)
+			if err != nil {
+				return nil, err
+			}
+			if m.Fields == nil {
+				m.Fields = map[string]json.RawMessage{}
+			}
+			m.Fields[k] = json.RawMessage(val)
+		case fieldCause, fieldErrors:
+			sub, err := decode(v, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if num == fieldCause {
+				m.Cause = sub
+			} else {
+				m.Errors = append(m.Errors, sub)
+			}
+		}
+	}
+	return m, nil
+}
+
+// decodeEntry decodes a metadata map entry.
+func decodeEntry(b []byte) (key, value string, err error) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", "", fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		} else {
+			var v []byte
+			v, n = protowire.ConsumeBytes(b)
+			switch num {
+			case 1:
+				key = string(v)
+			case 2:
+				value = string(v)
+			}
+		}
+		if n < 0 {
+			return "", "", fmt.Errorf("sneterrpb: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	if !json.Valid([]byte(value)) {
+		return "", "", fmt.Errorf("sneterrpb: field %q is not valid JSON", key)
+	}
+	return key, value, nil
+}
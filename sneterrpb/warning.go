@@ -0,0 +1,110 @@
+package sneterrpb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// WarningsKey is the key of the gRPC metadata carrying the warnings of a
+// response, one sneterr.v1.Warning message per value. Being a binary key,
+// its values are base64 encoded on the wire by gRPC.
+const WarningsKey = "sneterr-warnings-bin"
+
+// MarshalWarning returns the wire encoding of w as a sneterr.v1.Warning
+// message.
+func MarshalWarning(w sneterr.Warning) ([]byte, error) {
+	b := appendString(nil, fieldCode, w.Code)
+	b = appendString(b, fieldMessage, w.Message)
+	return appendFields(b, w.Fields)
+}
+
+// UnmarshalWarning rebuilds the warning from the wire encoding of a
+// sneterr.v1.Warning message. Fields are decoded like those of errors
+// received as JSON, numbers as float64.
+func UnmarshalWarning(b []byte) (sneterr.Warning, error) {
+	// Warning shares the field numbers of Error for code, message and
+	// metadata; the other fields of the decoded message are ignored.
+	m, err := decode(b, 0)
+	if err != nil {
+		return sneterr.Warning{}, err
+	}
+	w := sneterr.Warning{Code: m.Code, Message: m.Message}
+	for k, raw := range m.Fields {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return sneterr.Warning{}, fmt.Errorf("sneterrpb: field %q: %w", k, err)
+		}
+		if w.Fields == nil {
+			w.Fields = sneterr.Fields{}
+		}
+		w.Fields[k] = v
+	}
+	return w, nil
+}
+
+// AppendWarnings adds ws to the gRPC metadata md, under WarningsKey, to
+// attach them to a successful response as trailers, mirroring the
+// "warnings" array of HTTP responses. md is typically a metadata.MD:
+//
+//	md := metadata.MD{}
+//	if err := sneterrpb.AppendWarnings(md, ws.List()); err != nil {
+//		return nil, err
+//	}
+//	grpc.SetTrailer(ctx, md)
+func AppendWarnings(md map[string][]string, ws []sneterr.Warning) error {
+	for _, w := range ws {
+		b, err := MarshalWarning(w)
+		if err != nil {
+			return err
+		}
+		md[WarningsKey] = append(md[WarningsKey], string(b))
+	}
+	return nil
+}
+
+// TrailerFromContext returns the gRPC metadata carrying the warnings
+// collected by the sneterr.Warnings of ctx, see sneterr.ContextWithWarnings,
+// or nil if there are none, for servers to set as trailers once a call
+// is served:
+//
+//	if md, err := sneterrpb.TrailerFromContext(ctx); err == nil && md != nil {
+//		grpc.SetTrailer(ctx, md)
+//	}
+func TrailerFromContext(ctx context.Context) (map[string][]string, error) {
+	ws := sneterr.WarningsFromContext(ctx)
+	if ws == nil {
+		return nil, nil
+	}
+	list := ws.List()
+	if len(list) == 0 {
+		return nil, nil
+	}
+	md := map[string][]string{}
+	if err := AppendWarnings(md, list); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// WarningsFromTrailer returns the warnings carried by the gRPC trailer md
+// of a response, in the order they were added, such as the one received
+// with the grpc.Trailer call option:
+//
+//	var trailer metadata.MD
+//	resp, err := client.GetOrder(ctx, req, grpc.Trailer(&trailer))
+//	...
+//	ws, err := sneterrpb.WarningsFromTrailer(trailer)
+func WarningsFromTrailer(md map[string][]string) ([]sneterr.Warning, error) {
+	var ws []sneterr.Warning
+	for _, v := range md[WarningsKey] {
+		w, err := UnmarshalWarning([]byte(v))
+		if err != nil {
+			return nil, err
+		}
+		ws = append(ws, w)
+	}
+	return ws, nil
+}
@@ -0,0 +1,34 @@
+// Package sneterrprom exports sneterr error creation as Prometheus metrics.
+package sneterrprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/servicenetjp/sneterr"
+)
+
+// NewCounter returns the counter used by Hook, labeled by code and
+// severity.
+func NewCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "sneterr_errors_total",
+		Help: "Number of errors created, by code and severity.",
+	}, []string{"code", "severity"})
+}
+
+// Hook returns a sneterr.Hook incrementing c for every created error.
+func Hook(c *prometheus.CounterVec) sneterr.Hook {
+	return func(err sneterr.Error) {
+		c.WithLabelValues(err.Code(), sneterr.SeverityOf(err).String()).Inc()
+	}
+}
+
+// Register creates the error counter, registers it with reg and installs
+// the hook incrementing it.
+func Register(reg prometheus.Registerer) error {
+	c := NewCounter()
+	if err := reg.Register(c); err != nil {
+		return err
+	}
+	sneterr.RegisterHook(Hook(c))
+	return nil
+}
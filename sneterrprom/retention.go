@@ -0,0 +1,77 @@
+package sneterrprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/servicenetjp/sneterr"
+	"github.com/servicenetjp/sneterr/journal"
+)
+
+var (
+	recorderEntriesDesc = prometheus.NewDesc("sneterr_recorder_entries",
+		"Number of errors kept by the recorder.", []string{"recorder"}, nil)
+	recorderBytesDesc = prometheus.NewDesc("sneterr_recorder_bytes",
+		"Size of the errors kept by the recorder.", []string{"recorder"}, nil)
+	recorderEvictedDesc = prometheus.NewDesc("sneterr_recorder_evicted_total",
+		"Number of errors evicted by the recorder retention policy.", []string{"recorder"}, nil)
+
+	janitorSegmentsDesc = prometheus.NewDesc("sneterr_journal_segments",
+		"Number of sealed journal segments kept.", []string{"journal"}, nil)
+	janitorBytesDesc = prometheus.NewDesc("sneterr_journal_bytes",
+		"Size on disk of the sealed journal segments kept.", []string{"journal"}, nil)
+	janitorRemovedDesc = prometheus.NewDesc("sneterr_journal_segments_removed_total",
+		"Number of journal segments removed by the retention policy.", []string{"journal"}, nil)
+	janitorLastRunDesc = prometheus.NewDesc("sneterr_journal_janitor_last_run_timestamp_seconds",
+		"When the journal retention was last enforced.", []string{"journal"}, nil)
+)
+
+// RecorderCollector returns a collector exporting the state of r, labeled
+// with the recorder name.
+func RecorderCollector(name string, r *sneterr.Recorder) prometheus.Collector {
+	return recorderCollector{name: name, recorder: r}
+}
+
+type recorderCollector struct {
+	name     string
+	recorder *sneterr.Recorder
+}
+
+func (c recorderCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- recorderEntriesDesc
+	ch <- recorderBytesDesc
+	ch <- recorderEvictedDesc
+}
+
+func (c recorderCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.recorder.Stats()
+	ch <- prometheus.MustNewConstMetric(recorderEntriesDesc, prometheus.GaugeValue, float64(st.Entries), c.name)
+	ch <- prometheus.MustNewConstMetric(recorderBytesDesc, prometheus.GaugeValue, float64(st.Bytes), c.name)
+	ch <- prometheus.MustNewConstMetric(recorderEvictedDesc, prometheus.CounterValue, float64(st.Evicted), c.name)
+}
+
+// JanitorCollector returns a collector exporting the state of the journal
+// segments kept by j, labeled with the journal name.
+func JanitorCollector(name string, j *journal.Janitor) prometheus.Collector {
+	return janitorCollector{name: name, janitor: j}
+}
+
+type janitorCollector struct {
+	name    string
+	janitor *journal.Janitor
+}
+
+func (c janitorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- janitorSegmentsDesc
+	ch <- janitorBytesDesc
+	ch <- janitorRemovedDesc
+	ch <- janitorLastRunDesc
+}
+
+func (c janitorCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.janitor.Stats()
+	ch <- prometheus.MustNewConstMetric(janitorSegmentsDesc, prometheus.GaugeValue, float64(st.Segments), c.name)
+	ch <- prometheus.MustNewConstMetric(janitorBytesDesc, prometheus.GaugeValue, float64(st.Bytes), c.name)
+	ch <- prometheus.MustNewConstMetric(janitorRemovedDesc, prometheus.CounterValue, float64(st.Removed), c.name)
+	if !st.LastRun.IsZero() {
+		ch <- prometheus.MustNewConstMetric(janitorLastRunDesc, prometheus.GaugeValue, float64(st.LastRun.Unix()), c.name)
+	}
+}
@@ -0,0 +1,39 @@
+package sneterrprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/servicenetjp/sneterr/journal"
+)
+
+var (
+	spillPendingDesc = prometheus.NewDesc("sneterr_spill_pending_events",
+		"Number of events waiting in the spill queue.", []string{"sink"}, nil)
+	spillBytesDesc = prometheus.NewDesc("sneterr_spill_bytes",
+		"Size in bytes of the on-disk spill queue.", []string{"sink"}, nil)
+	spillLagDesc = prometheus.NewDesc("sneterr_spill_replay_lag_seconds",
+		"Age of the oldest event waiting in the spill queue.", []string{"sink"}, nil)
+)
+
+// SpillCollector returns a collector exporting the backlog of s, labeled
+// with the sink name.
+func SpillCollector(name string, s *journal.Spill) prometheus.Collector {
+	return spillCollector{name: name, spill: s}
+}
+
+type spillCollector struct {
+	name  string
+	spill *journal.Spill
+}
+
+func (c spillCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- spillPendingDesc
+	ch <- spillBytesDesc
+	ch <- spillLagDesc
+}
+
+func (c spillCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.spill.Stats()
+	ch <- prometheus.MustNewConstMetric(spillPendingDesc, prometheus.GaugeValue, float64(st.Pending), c.name)
+	ch <- prometheus.MustNewConstMetric(spillBytesDesc, prometheus.GaugeValue, float64(st.Bytes), c.name)
+	ch <- prometheus.MustNewConstMetric(spillLagDesc, prometheus.GaugeValue, st.ReplayLag.Seconds(), c.name)
+}
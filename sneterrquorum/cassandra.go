@@ -0,0 +1,100 @@
+package sneterrquorum
+
+import (
+	"errors"
+
+	"github.com/gocql/gocql"
+	"github.com/servicenetjp/sneterr"
+)
+
+// FromCassandraError translates err when it has an unavailable, timeout
+// or failure error of github.com/gocql/gocql in its chain.
+//
+// Following the default retry policy of the drivers, a write timeout is
+// only reported retryable for batch log writes, which Cassandra replays
+// itself: other writes may have been applied. Read timeouts are reported
+// retryable.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil
+// FromCassandraError returns nil.
+func FromCassandraError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	f, ok := cassandraFailure(err)
+	if !ok {
+		return passThrough(err)
+	}
+	return f.Err(err)
+}
+
+// cassandraFailure returns the failure described by the gocql error in
+// the chain of err.
+func cassandraFailure(err error) (Failure, bool) {
+	var (
+		unavailable  *gocql.RequestErrUnavailable
+		readTimeout  *gocql.RequestErrReadTimeout
+		writeTimeout *gocql.RequestErrWriteTimeout
+		readFailure  *gocql.RequestErrReadFailure
+		writeFailure *gocql.RequestErrWriteFailure
+	)
+	switch {
+	case errors.As(err, &unavailable):
+		return Failure{
+			Code:        ErrCodeUnavailable,
+			Consistency: unavailable.Consistency.String(),
+			Required:    unavailable.Required,
+			Alive:       unavailable.Alive,
+			Received:    -1,
+			Failures:    -1,
+		}, true
+	case errors.As(err, &readTimeout):
+		present := readTimeout.DataPresent != 0
+		return Failure{
+			Code:        ErrCodeReadTimeout,
+			Consistency: readTimeout.Consistency.String(),
+			Required:    readTimeout.BlockFor,
+			Alive:       -1,
+			Received:    readTimeout.Received,
+			Failures:    -1,
+			DataPresent: &present,
+		}, true
+	case errors.As(err, &writeTimeout):
+		retryable := writeTimeout.WriteType == "BATCH_LOG"
+		return Failure{
+			Code:        ErrCodeWriteTimeout,
+			Consistency: writeTimeout.Consistency.String(),
+			Required:    writeTimeout.BlockFor,
+			Alive:       -1,
+			Received:    writeTimeout.Received,
+			Failures:    -1,
+			WriteType:   writeTimeout.WriteType,
+			Retryable:   &retryable,
+		}, true
+	case errors.As(err, &readFailure):
+		present := readFailure.DataPresent
+		return Failure{
+			Code:        ErrCodeReadFailure,
+			Consistency: readFailure.Consistency.String(),
+			Required:    readFailure.BlockFor,
+			Alive:       -1,
+			Received:    readFailure.Received,
+			Failures:    readFailure.NumFailures,
+			DataPresent: &present,
+		}, true
+	case errors.As(err, &writeFailure):
+		return Failure{
+			Code:        ErrCodeWriteFailure,
+			Consistency: writeFailure.Consistency.String(),
+			Required:    writeFailure.BlockFor,
+			Alive:       -1,
+			Received:    writeFailure.Received,
+			Failures:    writeFailure.NumFailures,
+			WriteType:   writeFailure.WriteType,
+		}, true
+	}
+	return Failure{}, false
+}
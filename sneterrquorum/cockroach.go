@@ -0,0 +1,59 @@
+package sneterrquorum
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// SQLSTATE codes reported by CockroachDB.
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateCompletionUnknown    = "40003"
+	sqlStateRangeUnavailable     = "58C00"
+)
+
+// FromCockroachError translates err when it has an error with one of the
+// SQLSTATE codes CockroachDB reports for transaction retries, ambiguous
+// commits and unavailable ranges in its chain. The SQLSTATE is read from
+// a SQLState() string method, as implemented by the errors of
+// github.com/jackc/pgx and github.com/lib/pq.
+//
+// CockroachDB does not report replica counts: only the SQLSTATE and, for
+// transaction retries, the consistency level are recorded.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil
+// FromCockroachError returns nil.
+func FromCockroachError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return passThrough(err)
+	}
+
+	f := Failure{Required: -1, Alive: -1, Received: -1, Failures: -1}
+	state := pgErr.SQLState()
+	switch state {
+	case sqlStateSerializationFailure:
+		f.Code = ErrCodeTransactionRetry
+		f.Consistency = "SERIALIZABLE"
+	case sqlStateCompletionUnknown:
+		f.Code = ErrCodeAmbiguousResult
+	case sqlStateRangeUnavailable:
+		f.Code = ErrCodeRangeUnavailable
+	default:
+		if !strings.HasPrefix(state, "40") {
+			return passThrough(err)
+		}
+		// Other transaction rollbacks, such as deadlocks, are retried
+		// like serialization failures.
+		f.Code = ErrCodeTransactionRetry
+	}
+	return f.Err(err, sneterr.WithField(FieldSQLState, state))
+}
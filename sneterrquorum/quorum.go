@@ -0,0 +1,172 @@
+// Package sneterrquorum translates the consistency and quorum failures of
+// distributed stores, such as Cassandra and CockroachDB, into dedicated
+// codes. The consistency level, the replicas required and alive and
+// whether the operation can be retried are recorded as fields, so
+// capacity incidents can be diagnosed from the error alone.
+package sneterrquorum
+
+import (
+	"net/http"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// Codes of the errors returned by the converters.
+const (
+	// Too few replicas were alive to attempt the operation.
+	ErrCodeUnavailable = "QuorumUnavailable"
+
+	// Too few replicas answered a read in time.
+	ErrCodeReadTimeout = "QuorumReadTimeout"
+
+	// Too few replicas acknowledged a write in time. The write may still
+	// have been applied.
+	ErrCodeWriteTimeout = "QuorumWriteTimeout"
+
+	// Replicas failed to serve a read.
+	ErrCodeReadFailure = "QuorumReadFailure"
+
+	// Replicas failed to apply a write.
+	ErrCodeWriteFailure = "QuorumWriteFailure"
+
+	// The range holding the data lost its quorum.
+	ErrCodeRangeUnavailable = "QuorumRangeUnavailable"
+
+	// The transaction conflicted with another one and must be retried.
+	ErrCodeTransactionRetry = "QuorumTransactionRetry"
+
+	// Whether the transaction committed is unknown.
+	ErrCodeAmbiguousResult = "QuorumAmbiguousResult"
+)
+
+// Field keys of the errors returned by the converters.
+const (
+	// The consistency level requested, such as "QUORUM".
+	FieldConsistency = "consistency"
+
+	// How many replicas had to answer.
+	FieldRequired = "required_replicas"
+
+	// How many replicas were alive.
+	FieldAlive = "alive_replicas"
+
+	// How many replicas answered.
+	FieldReceived = "received_replicas"
+
+	// How many replicas failed.
+	FieldFailures = "failed_replicas"
+
+	// The kind of write which timed out or failed, such as "SIMPLE" or
+	// "BATCH_LOG".
+	FieldWriteType = "write_type"
+
+	// Whether the replica asked for the data answered, for reads.
+	FieldDataPresent = "data_present"
+
+	// The SQLSTATE reported by the database.
+	FieldSQLState = "sqlstate"
+)
+
+func init() {
+	schema := sneterr.Schema{
+		FieldConsistency:       sneterr.FieldString,
+		FieldRequired:          sneterr.FieldInt,
+		FieldAlive:             sneterr.FieldInt,
+		FieldReceived:          sneterr.FieldInt,
+		FieldFailures:          sneterr.FieldInt,
+		FieldWriteType:         sneterr.FieldString,
+		FieldDataPresent:       sneterr.FieldBool,
+		FieldSQLState:          sneterr.FieldString,
+		sneterr.FieldRetryable: sneterr.FieldBool,
+	}
+	for _, info := range []sneterr.CodeInfo{
+		{Code: ErrCodeUnavailable, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeReadTimeout, Category: sneterr.CategoryTimeout, Retryable: true, Access: sneterr.AccessRead},
+		{Code: ErrCodeWriteTimeout, Category: sneterr.CategoryTimeout, Access: sneterr.AccessWrite},
+		{Code: ErrCodeReadFailure, Category: sneterr.CategoryUnavailable, Access: sneterr.AccessRead},
+		{Code: ErrCodeWriteFailure, Category: sneterr.CategoryUnavailable, Access: sneterr.AccessWrite},
+		{Code: ErrCodeRangeUnavailable, Category: sneterr.CategoryUnavailable, Severity: sneterr.SeverityCritical},
+		{Code: ErrCodeTransactionRetry, Category: sneterr.CategoryConflict, Retryable: true, Severity: sneterr.SeverityWarn},
+		{Code: ErrCodeAmbiguousResult, Category: sneterr.CategoryUnavailable, Status: http.StatusInternalServerError, Access: sneterr.AccessWrite},
+	} {
+		info.Fields = schema
+		sneterr.Register(info)
+	}
+}
+
+// messages are the messages of the errors, which do not include the
+// message of the store.
+var messages = map[string]string{
+	ErrCodeUnavailable:      "not enough replicas are available",
+	ErrCodeReadTimeout:      "not enough replicas answered the read in time",
+	ErrCodeWriteTimeout:     "not enough replicas acknowledged the write in time",
+	ErrCodeReadFailure:      "replicas failed to serve the read",
+	ErrCodeWriteFailure:     "replicas failed to apply the write",
+	ErrCodeRangeUnavailable: "the data is unavailable until its replicas recover",
+	ErrCodeTransactionRetry: "the transaction conflicted with another one",
+	ErrCodeAmbiguousResult:  "whether the transaction committed is unknown",
+}
+
+// A Failure describes a consistency or quorum failure reported by a
+// store. Counts which the store did not report are negative.
+type Failure struct {
+	// Code is one of the codes of the package.
+	Code string
+
+	Consistency string
+	Required    int
+	Alive       int
+	Received    int
+	Failures    int
+	WriteType   string
+
+	// DataPresent is only reported for reads.
+	DataPresent *bool
+
+	// Retryable overrides the retryability registered for Code when set.
+	Retryable *bool
+}
+
+// Err returns the error describing f, wrapping cause, configured by
+// opts.
+func (f Failure) Err(cause error, opts ...sneterr.Option) sneterr.Error {
+	sneterr.Helper()
+
+	opts = append([]sneterr.Option{sneterr.WithCause(cause)}, opts...)
+	if f.Consistency != "" {
+		opts = append(opts, sneterr.WithField(FieldConsistency, f.Consistency))
+	}
+	for _, c := range []struct {
+		key string
+		n   int
+	}{
+		{FieldRequired, f.Required},
+		{FieldAlive, f.Alive},
+		{FieldReceived, f.Received},
+		{FieldFailures, f.Failures},
+	} {
+		if c.n >= 0 {
+			opts = append(opts, sneterr.WithField(c.key, c.n))
+		}
+	}
+	if f.WriteType != "" {
+		opts = append(opts, sneterr.WithField(FieldWriteType, f.WriteType))
+	}
+	if f.DataPresent != nil {
+		opts = append(opts, sneterr.WithField(FieldDataPresent, *f.DataPresent))
+	}
+	if f.Retryable != nil {
+		opts = append(opts, sneterr.WithRetryable(*f.Retryable))
+	}
+	return sneterr.New(f.Code, messages[f.Code], opts...)
+}
+
+// passThrough returns err, which is not a quorum failure, as an Error.
+func passThrough(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if e, ok := err.(sneterr.Error); ok {
+		return e
+	}
+	return sneterr.Wrap(err, sneterr.ErrCodeUnknown, err.Error())
+}
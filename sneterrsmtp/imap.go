@@ -0,0 +1,65 @@
+package sneterrsmtp
+
+import (
+	"strings"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// imapCodes maps the IMAP response codes of RFC 5530 to error codes.
+var imapCodes = map[string]string{
+	"AUTHENTICATIONFAILED": ErrCodeMailAuthFailed,
+	"AUTHORIZATIONFAILED":  ErrCodeMailAuthFailed,
+	"EXPIRED":              ErrCodeMailAuthFailed,
+	"OVERQUOTA":            ErrCodeMailboxFull,
+	"LIMIT":                ErrCodeMailboxFull,
+	"NONEXISTENT":          ErrCodeMailboxUnavailable,
+	"UNAVAILABLE":          ErrCodeMailServerBusy,
+	"INUSE":                ErrCodeMailTransient,
+	"SERVERBUG":            ErrCodeMailTransient,
+	"CANNOT":               ErrCodeMailPermanent,
+}
+
+// FromIMAPResponse translates a tagged IMAP response with status, "NO" or
+// "BAD", and text into an error, or returns nil for an "OK" response. The
+// response code in brackets starting text, such as "[OVERQUOTA]", chooses
+// the code of the error when it is known; otherwise NO responses are
+// transient failures and BAD responses permanent ones.
+func FromIMAPResponse(status, text string) sneterr.Error {
+	sneterr.Helper()
+
+	status = strings.ToUpper(status)
+	if status == "OK" {
+		return nil
+	}
+
+	respCode := imapResponseCode(text)
+	errCode, ok := imapCodes[respCode]
+	if !ok {
+		errCode = ErrCodeMailPermanent
+		if status == "NO" {
+			errCode = ErrCodeMailTransient
+		}
+	}
+
+	opts := []sneterr.Option{sneterr.WithField(FieldServerMessage, text)}
+	if respCode != "" {
+		opts = append(opts, sneterr.WithField(FieldStatusCode, respCode))
+	}
+	return sneterr.New(errCode, messages[errCode], opts...)
+}
+
+// imapResponseCode returns the atom of the response code starting text,
+// without its arguments.
+func imapResponseCode(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "[") {
+		return ""
+	}
+	code, _, ok := strings.Cut(text[1:], "]")
+	if !ok {
+		return ""
+	}
+	code, _, _ = strings.Cut(code, " ")
+	return strings.ToUpper(code)
+}
@@ -0,0 +1,179 @@
+// Package sneterrsmtp translates the replies of SMTP and IMAP servers into
+// coded errors, telling the transient failures worth retrying from the
+// permanent ones.
+package sneterrsmtp
+
+import (
+	"errors"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// Codes of the errors returned by FromError and FromReply.
+const (
+	ErrCodeMailboxUnavailable = "MailboxUnavailable"
+	ErrCodeMailboxFull        = "MailboxFull"
+	ErrCodeMessageRejected    = "MessageRejected"
+	ErrCodeMailAuthFailed     = "MailAuthFailed"
+	ErrCodeMailServerBusy     = "MailServerBusy"
+	ErrCodeMailTransient      = "MailTransient"
+	ErrCodeMailPermanent      = "MailPermanent"
+)
+
+// Field keys of the errors returned by FromError and FromReply.
+const (
+	// The basic reply code of an SMTP server, such as 550.
+	FieldReplyCode = "smtp_reply_code"
+
+	// The enhanced status code of an SMTP server, such as "5.1.1", or the
+	// response code of an IMAP server, such as "OVERQUOTA".
+	FieldStatusCode = "mail_status_code"
+
+	// The text of the reply of the server. It can name recipients, so it
+	// is not meant to be shown to clients.
+	FieldServerMessage = "mail_server_message"
+)
+
+func init() {
+	schema := sneterr.Schema{
+		FieldReplyCode:     sneterr.FieldInt,
+		FieldStatusCode:    sneterr.FieldString,
+		FieldServerMessage: sneterr.FieldString,
+	}
+	for _, info := range []sneterr.CodeInfo{
+		{Code: ErrCodeMailboxUnavailable, Category: sneterr.CategoryNotFound, Status: http.StatusUnprocessableEntity},
+		{Code: ErrCodeMailboxFull, Category: sneterr.CategoryResourceExhausted, Retryable: true},
+		{Code: ErrCodeMessageRejected, Category: sneterr.CategoryInvalidArgument, Status: http.StatusUnprocessableEntity},
+		{Code: ErrCodeMailAuthFailed, Category: sneterr.CategoryUnauthenticated, Status: http.StatusBadGateway},
+		{Code: ErrCodeMailServerBusy, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeMailTransient, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeMailPermanent, Category: sneterr.CategoryInternal, Status: http.StatusBadGateway},
+	} {
+		info.Fields = schema
+		sneterr.Register(info)
+	}
+}
+
+// messages are the messages of the errors, which do not include the reply
+// of the server.
+var messages = map[string]string{
+	ErrCodeMailboxUnavailable: "the recipient mailbox is unavailable",
+	ErrCodeMailboxFull:        "the recipient mailbox is full",
+	ErrCodeMessageRejected:    "the message was rejected by the mail server",
+	ErrCodeMailAuthFailed:     "authentication with the mail server failed",
+	ErrCodeMailServerBusy:     "the mail server is temporarily unavailable",
+	ErrCodeMailTransient:      "the mail server failed temporarily",
+	ErrCodeMailPermanent:      "the mail server refused the request",
+}
+
+// FromError translates err, as returned by net/smtp, when it has a
+// *textproto.Error in its chain. The error is given a code according to
+// the reply code and the enhanced status code of RFC 3463, if the reply
+// starts with one. Transient failures, with a 4xx reply code or a 4.x.x
+// status, have retryable codes such as ErrCodeMailTransient.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil FromError
+// returns nil.
+func FromError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var tpErr *textproto.Error
+	if !errors.As(err, &tpErr) {
+		if e, ok := err.(sneterr.Error); ok {
+			return e
+		}
+		return sneterr.Wrap(err, sneterr.ErrCodeUnknown, err.Error())
+	}
+	return fromReply(tpErr.Code, tpErr.Msg, err)
+}
+
+// FromReply translates the SMTP reply with code and msg into an error, or
+// returns nil if code does not report a failure.
+func FromReply(code int, msg string) sneterr.Error {
+	sneterr.Helper()
+
+	if code < 400 {
+		return nil
+	}
+	return fromReply(code, msg, nil)
+}
+
+func fromReply(code int, msg string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	status := enhancedStatus(msg)
+	errCode := classifyReply(code, status)
+	opts := []sneterr.Option{
+		sneterr.WithCause(cause),
+		sneterr.WithField(FieldReplyCode, code),
+		sneterr.WithField(FieldServerMessage, msg),
+	}
+	if status != "" {
+		opts = append(opts, sneterr.WithField(FieldStatusCode, status))
+	}
+	return sneterr.New(errCode, messages[errCode], opts...)
+}
+
+// enhancedStatus returns the enhanced status code starting msg, if any.
+func enhancedStatus(msg string) string {
+	status, _, _ := strings.Cut(strings.TrimSpace(msg), " ")
+	parts := strings.Split(status, ".")
+	if len(parts) != 3 || (parts[0] != "2" && parts[0] != "4" && parts[0] != "5") {
+		return ""
+	}
+	for _, p := range parts[1:] {
+		if n, err := strconv.Atoi(p); err != nil || n < 0 || n > 999 {
+			return ""
+		}
+	}
+	return status
+}
+
+// classifyReply returns the code of the error for a reply code and an
+// enhanced status, which is more precise when present.
+func classifyReply(code int, status string) string {
+	transient := code < 500
+	if status != "" {
+		transient = status[0] == '4'
+		subject, detail, _ := strings.Cut(status[2:], ".")
+		switch {
+		case subject == "1" && !transient:
+			return ErrCodeMailboxUnavailable
+		case subject == "2" && detail == "2":
+			return ErrCodeMailboxFull
+		case subject == "7" && (detail == "8" || detail == "0" && code == 535):
+			return ErrCodeMailAuthFailed
+		case (subject == "6" || subject == "7") && !transient:
+			return ErrCodeMessageRejected
+		}
+	}
+
+	switch code {
+	case 421:
+		return ErrCodeMailServerBusy
+	case 452, 552:
+		return ErrCodeMailboxFull
+	case 535:
+		return ErrCodeMailAuthFailed
+	case 550, 551, 553:
+		if !transient {
+			return ErrCodeMailboxUnavailable
+		}
+	case 554:
+		if !transient {
+			return ErrCodeMessageRejected
+		}
+	}
+	if transient {
+		return ErrCodeMailTransient
+	}
+	return ErrCodeMailPermanent
+}
@@ -0,0 +1,141 @@
+// Package sneterrtest provides test assertions on sneterr errors which do
+// not depend on the layout of their Error string.
+package sneterrtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// UpdateEnv is the environment variable which, when set to a non-empty
+// value, makes MustMatch rewrite the golden files instead of comparing
+// against them.
+const UpdateEnv = "SNETERR_UPDATE_GOLDEN"
+
+// volatileKeys are the members of the JSON encoding of errors which change
+// between runs or when code moves around.
+var volatileKeys = []string{"file", "line", "time", "duration", "fingerprint"}
+
+// AssertCode reports whether the first Error in the chain of err has code,
+// marking the test as failed if it does not.
+func AssertCode(t testing.TB, err error, code string) bool {
+	t.Helper()
+
+	var e sneterr.Error
+	if !errors.As(err, &e) {
+		t.Errorf("error %v has no code, want %q", err, code)
+		return false
+	}
+	if e.Code() != code {
+		t.Errorf("error code is %q, want %q", e.Code(), code)
+		return false
+	}
+	return true
+}
+
+// AssertWraps reports whether errors.Is(err, target), marking the test as
+// failed if it does not.
+func AssertWraps(t testing.TB, err, target error) bool {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Errorf("error %v does not wrap %v", err, target)
+		return false
+	}
+	return true
+}
+
+// AssertFields reports whether err carries every field of want with an
+// equal value, marking the test as failed if it does not. Fields of err
+// which are not in want are ignored.
+func AssertFields(t testing.TB, err error, want sneterr.Fields) bool {
+	t.Helper()
+
+	got := sneterr.FieldsOf(err)
+	ok := true
+	for k, w := range want {
+		g, set := got[k]
+		switch {
+		case !set:
+			t.Errorf("error has no field %q, want %#v", k, w)
+			ok = false
+		case !reflect.DeepEqual(g, w):
+			t.Errorf("error field %q is %#v, want %#v", k, g, w)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// MustMatch compares the JSON encoding of err with the golden file at
+// path, stopping the test if they differ. Locations, timestamps, durations
+// and fingerprints are left out of the comparison.
+//
+// When the UpdateEnv environment variable is set, the golden file is
+// written with the encoding of err instead.
+func MustMatch(t testing.TB, err error, path string) {
+	t.Helper()
+
+	got, mErr := Normalize(err)
+	if mErr != nil {
+		t.Fatalf("cannot encode error: %v", mErr)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("cannot write golden file: %v", err)
+		}
+		return
+	}
+
+	want, rErr := os.ReadFile(path)
+	if rErr != nil {
+		t.Fatalf("cannot read golden file (set %s=1 to create it): %v", UpdateEnv, rErr)
+	}
+	if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+		t.Fatalf("error does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// Normalize returns the indented JSON encoding of err used by MustMatch,
+// without its volatile members.
+func Normalize(err error) ([]byte, error) {
+	b, mErr := json.Marshal(err)
+	if mErr != nil {
+		return nil, mErr
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	strip(v)
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// strip removes the volatile keys from every object in v.
+func strip(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, k := range volatileKeys {
+			delete(v, k)
+		}
+		for _, c := range v {
+			strip(c)
+		}
+	case []interface{}:
+		for _, c := range v {
+			strip(c)
+		}
+	}
+}
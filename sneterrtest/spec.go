@@ -0,0 +1,103 @@
+package sneterrtest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/servicenetjp/sneterr"
+	"gopkg.in/yaml.v3"
+)
+
+// A Spec describes the shape of an error agreed between a provider and
+// its consumers, for contract tests. Unset members are not checked.
+type Spec struct {
+	// The code of the first Error in the chain.
+	Code string `yaml:"code" json:"code"`
+
+	// The category of the error, as returned by sneterr.CategoryOf.
+	Category sneterr.Category `yaml:"category" json:"category"`
+
+	// The HTTP status of the error, as returned by sneterr.HTTPStatus.
+	Status int `yaml:"status" json:"status"`
+
+	// The keys of the fields the error must carry.
+	Fields []string `yaml:"fields" json:"fields"`
+}
+
+// LoadSpecs reads the specs of the YAML or JSON file at path, a mapping
+// of spec names to specs:
+//
+//	order-not-found:
+//	  code: OrderNotFound
+//	  category: NotFound
+//	  status: 404
+//	  fields: [order_id]
+func LoadSpecs(path string) (map[string]Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	specs, err := ParseSpecs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return specs, nil
+}
+
+// ParseSpecs parses specs in the format read by LoadSpecs.
+func ParseSpecs(data []byte) (map[string]Spec, error) {
+	var specs map[string]Spec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		return nil, err
+	}
+	return specs, nil
+}
+
+// Conforms checks that err has the shape described by spec, comparing its
+// code, category, status and field keys rather than its message, and
+// returns an error listing every mismatch, or nil if it conforms. It
+// works as well on the errors decoded from the responses of a provider
+// by sneterr.FromResponse.
+func Conforms(err error, spec Spec) error {
+	if err == nil {
+		return errors.New("error is nil")
+	}
+
+	var errs []error
+	if spec.Code != "" {
+		if code := sneterr.CodeOf(err); code != spec.Code {
+			errs = append(errs, fmt.Errorf("code is %q, want %q", code, spec.Code))
+		}
+	}
+	if spec.Category != "" {
+		if cat := sneterr.CategoryOf(err); cat != spec.Category {
+			errs = append(errs, fmt.Errorf("category is %q, want %q", cat, spec.Category))
+		}
+	}
+	if spec.Status != 0 {
+		if status := sneterr.HTTPStatus(err); status != spec.Status {
+			errs = append(errs, fmt.Errorf("status is %d, want %d", status, spec.Status))
+		}
+	}
+	fields := sneterr.FieldsOf(err)
+	for _, k := range spec.Fields {
+		if _, ok := fields[k]; !ok {
+			errs = append(errs, fmt.Errorf("field %q is missing", k))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AssertConforms reports whether err conforms to spec, see Conforms,
+// marking the test as failed with every mismatch if it does not.
+func AssertConforms(t testing.TB, err error, spec Spec) bool {
+	t.Helper()
+
+	if cErr := Conforms(err, spec); cErr != nil {
+		t.Errorf("error %v does not conform to its spec:\n%v", err, cErr)
+		return false
+	}
+	return true
+}
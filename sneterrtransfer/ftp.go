@@ -0,0 +1,72 @@
+package sneterrtransfer
+
+import (
+	"errors"
+	"net/textproto"
+	"strconv"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// ftpCodes maps the reply codes of RFC 959 to error codes.
+var ftpCodes = map[int]string{
+	421: ErrCodeStorageUnavailable, // service not available
+	425: ErrCodeStorageUnavailable, // cannot open data connection
+	426: ErrCodeStorageUnavailable, // connection closed, transfer aborted
+	430: ErrCodeStorageAuthFailed,  // invalid user name or password
+	450: ErrCodeStorageUnavailable, // file unavailable, such as busy
+	451: ErrCodeStorageUnavailable, // local error in processing
+	452: ErrCodeStorageFull,        // insufficient storage space
+	500: ErrCodeInvalidFileRequest, // syntax error, command unrecognized
+	501: ErrCodeInvalidFileRequest, // syntax error in parameters
+	502: ErrCodeInvalidFileRequest, // command not implemented
+	504: ErrCodeInvalidFileRequest, // command not implemented for parameter
+	530: ErrCodeStorageAuthFailed,  // not logged in
+	532: ErrCodeStorageAuthFailed,  // need account for storing files
+	550: ErrCodeFileNotFound,       // file unavailable, not found or no access
+	552: ErrCodeStorageFull,        // exceeded storage allocation
+	553: ErrCodeInvalidFileRequest, // file name not allowed
+}
+
+// FromFTPError translates err when it has a *textproto.Error in its
+// chain, as returned by FTP clients such as github.com/jlaffaye/ftp.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil
+// FromFTPError returns nil.
+func FromFTPError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var tpErr *textproto.Error
+	if !errors.As(err, &tpErr) {
+		return passThrough(err)
+	}
+	return ftpError(tpErr.Code, err)
+}
+
+// FromFTPReply translates the FTP reply code into an error, or returns
+// nil if code does not report a failure.
+func FromFTPReply(code int) sneterr.Error {
+	sneterr.Helper()
+
+	if code < 400 {
+		return nil
+	}
+	return ftpError(code, nil)
+}
+
+func ftpError(code int, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	errCode, ok := ftpCodes[code]
+	if !ok {
+		errCode = ErrCodeTransferFailed
+		if code < 500 {
+			errCode = ErrCodeStorageUnavailable
+		}
+	}
+	return newError(errCode, ProtocolFTP, strconv.Itoa(code), cause)
+}
@@ -0,0 +1,86 @@
+package sneterrtransfer
+
+import (
+	"errors"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// s3Codes maps the error codes of S3-compatible object stores to error
+// codes.
+var s3Codes = map[string]string{
+	"NoSuchKey":                  ErrCodeFileNotFound,
+	"NoSuchBucket":               ErrCodeFileNotFound,
+	"NoSuchUpload":               ErrCodeFileNotFound,
+	"NoSuchVersion":              ErrCodeFileNotFound,
+	"NotFound":                   ErrCodeFileNotFound,
+	"AccessDenied":               ErrCodeFileAccessDenied,
+	"AllAccessDisabled":          ErrCodeFileAccessDenied,
+	"AccountProblem":             ErrCodeFileAccessDenied,
+	"InvalidObjectState":         ErrCodeFileAccessDenied,
+	"InvalidAccessKeyId":         ErrCodeStorageAuthFailed,
+	"SignatureDoesNotMatch":      ErrCodeStorageAuthFailed,
+	"ExpiredToken":               ErrCodeStorageAuthFailed,
+	"InvalidToken":               ErrCodeStorageAuthFailed,
+	"BucketAlreadyExists":        ErrCodeFileConflict,
+	"BucketAlreadyOwnedByYou":    ErrCodeFileConflict,
+	"BucketNotEmpty":             ErrCodeFileConflict,
+	"OperationAborted":           ErrCodeFileConflict,
+	"PreconditionFailed":         ErrCodeFileConflict,
+	"EntityTooLarge":             ErrCodeInvalidFileRequest,
+	"EntityTooSmall":             ErrCodeInvalidFileRequest,
+	"InvalidArgument":            ErrCodeInvalidFileRequest,
+	"InvalidBucketName":          ErrCodeInvalidFileRequest,
+	"InvalidRange":               ErrCodeInvalidFileRequest,
+	"KeyTooLongError":            ErrCodeInvalidFileRequest,
+	"MalformedXML":               ErrCodeInvalidFileRequest,
+	"QuotaExceeded":              ErrCodeStorageFull,
+	"XMinioStorageFull":          ErrCodeStorageFull,
+	"SlowDown":                   ErrCodeStorageThrottled,
+	"Throttling":                 ErrCodeStorageThrottled,
+	"TooManyRequests":            ErrCodeStorageThrottled,
+	"RequestLimitExceeded":       ErrCodeStorageThrottled,
+	"ServiceUnavailable":         ErrCodeStorageUnavailable,
+	"InternalError":              ErrCodeStorageUnavailable,
+	"RequestTimeout":             ErrCodeStorageUnavailable,
+	"XMinioServerNotInitialized": ErrCodeStorageUnavailable,
+}
+
+// FromS3Error translates err when it has an error with an ErrorCode()
+// string method in its chain, such as the smithy.APIError of the AWS SDK
+// for Go v2. Clients reporting the code otherwise, such as minio-go with
+// its ErrorResponse, can use FromS3Code.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil
+// FromS3Error returns nil.
+func FromS3Error(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var apiErr interface{ ErrorCode() string }
+	if !errors.As(err, &apiErr) {
+		return passThrough(err)
+	}
+	return s3Error(apiErr.ErrorCode(), err)
+}
+
+// FromS3Code translates the error code of an S3-compatible object store
+// into an error wrapping cause.
+func FromS3Code(code string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	return s3Error(code, cause)
+}
+
+func s3Error(code string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	errCode, ok := s3Codes[code]
+	if !ok {
+		errCode = ErrCodeTransferFailed
+	}
+	return newError(errCode, ProtocolS3, code, cause)
+}
@@ -0,0 +1,67 @@
+package sneterrtransfer
+
+import (
+	"errors"
+	"io/fs"
+	"strconv"
+
+	"github.com/pkg/sftp"
+	"github.com/servicenetjp/sneterr"
+)
+
+// sftpCode is a status code of the SFTP protocol.
+type sftpCode struct {
+	name string
+	code string
+}
+
+// sftpCodes maps the SSH_FX status codes to error codes.
+var sftpCodes = map[uint32]sftpCode{
+	2:  {"SSH_FX_NO_SUCH_FILE", ErrCodeFileNotFound},
+	3:  {"SSH_FX_PERMISSION_DENIED", ErrCodeFileAccessDenied},
+	4:  {"SSH_FX_FAILURE", ErrCodeTransferFailed},
+	5:  {"SSH_FX_BAD_MESSAGE", ErrCodeInvalidFileRequest},
+	6:  {"SSH_FX_NO_CONNECTION", ErrCodeStorageUnavailable},
+	7:  {"SSH_FX_CONNECTION_LOST", ErrCodeStorageUnavailable},
+	8:  {"SSH_FX_OP_UNSUPPORTED", ErrCodeInvalidFileRequest},
+	9:  {"SSH_FX_INVALID_HANDLE", ErrCodeInvalidFileRequest},
+	10: {"SSH_FX_NO_SUCH_PATH", ErrCodeFileNotFound},
+	11: {"SSH_FX_FILE_ALREADY_EXISTS", ErrCodeFileConflict},
+	12: {"SSH_FX_WRITE_PROTECT", ErrCodeFileAccessDenied},
+	14: {"SSH_FX_NO_SPACE_ON_FILESYSTEM", ErrCodeStorageFull},
+	15: {"SSH_FX_QUOTA_EXCEEDED", ErrCodeStorageFull},
+	17: {"SSH_FX_LOCK_CONFLICT", ErrCodeFileConflict},
+	18: {"SSH_FX_DIR_NOT_EMPTY", ErrCodeFileConflict},
+	20: {"SSH_FX_INVALID_FILENAME", ErrCodeInvalidFileRequest},
+}
+
+// FromSFTPError translates err when it has a *sftp.StatusError in its
+// chain. As github.com/pkg/sftp reports missing files and denied
+// permissions with fs.ErrNotExist and fs.ErrPermission, those are
+// translated too.
+//
+// Other errors are returned as they are, wrapped with ErrCodeUnknown when
+// they do not satisfy the sneterr.Error interface. If err is nil
+// FromSFTPError returns nil.
+func FromSFTPError(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	var status *sftp.StatusError
+	switch {
+	case errors.As(err, &status):
+		c, ok := sftpCodes[status.Code]
+		if !ok {
+			c = sftpCode{strconv.FormatUint(uint64(status.Code), 10), ErrCodeTransferFailed}
+		}
+		return newError(c.code, ProtocolSFTP, c.name, err)
+	case errors.Is(err, fs.ErrNotExist):
+		return newError(ErrCodeFileNotFound, ProtocolSFTP, sftpCodes[2].name, err)
+	case errors.Is(err, fs.ErrPermission):
+		return newError(ErrCodeFileAccessDenied, ProtocolSFTP, sftpCodes[3].name, err)
+	}
+	return passThrough(err)
+}
@@ -0,0 +1,122 @@
+// Package sneterrtransfer translates the errors of file transfers, over
+// SFTP, FTP or S3-compatible object stores, into a common set of codes
+// with retry hints, so transfer pipelines handle them the same way
+// whatever the protocol.
+package sneterrtransfer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/servicenetjp/sneterr"
+)
+
+// Codes of the errors returned by the converters.
+const (
+	ErrCodeFileNotFound       = "FileNotFound"
+	ErrCodeFileAccessDenied   = "FileAccessDenied"
+	ErrCodeFileConflict       = "FileConflict"
+	ErrCodeInvalidFileRequest = "InvalidFileRequest"
+	ErrCodeStorageAuthFailed  = "StorageAuthFailed"
+	ErrCodeStorageFull        = "StorageFull"
+	ErrCodeStorageThrottled   = "StorageThrottled"
+	ErrCodeStorageUnavailable = "StorageUnavailable"
+	ErrCodeTransferFailed     = "TransferFailed"
+)
+
+// Field keys of the errors returned by the converters.
+const (
+	// The protocol of the transfer: "sftp", "ftp" or "s3".
+	FieldProtocol = "protocol"
+
+	// The code reported by the remote side, such as "550" for FTP,
+	// "SSH_FX_NO_SUCH_FILE" for SFTP or "NoSuchKey" for S3.
+	FieldRemoteCode = "remote_code"
+
+	// How long to wait before retrying, for retryable errors.
+	FieldRetryAfter = "retry_after"
+)
+
+// Protocols of the FieldProtocol field.
+const (
+	ProtocolSFTP = "sftp"
+	ProtocolFTP  = "ftp"
+	ProtocolS3   = "s3"
+)
+
+// RetryAfter holds the delays recorded in the FieldRetryAfter field of
+// retryable errors, by code. It must not be modified once errors are
+// translated.
+var RetryAfter = map[string]time.Duration{
+	ErrCodeStorageThrottled:   time.Second,
+	ErrCodeStorageUnavailable: 250 * time.Millisecond,
+	ErrCodeStorageFull:        time.Minute,
+}
+
+func init() {
+	schema := sneterr.Schema{
+		FieldProtocol:   sneterr.FieldString,
+		FieldRemoteCode: sneterr.FieldString,
+		FieldRetryAfter: sneterr.FieldAny,
+	}
+	for _, info := range []sneterr.CodeInfo{
+		{Code: ErrCodeFileNotFound, Category: sneterr.CategoryNotFound, Access: sneterr.AccessRead},
+		{Code: ErrCodeFileAccessDenied, Category: sneterr.CategoryPermissionDenied},
+		{Code: ErrCodeFileConflict, Category: sneterr.CategoryConflict, Access: sneterr.AccessWrite},
+		{Code: ErrCodeInvalidFileRequest, Category: sneterr.CategoryInvalidArgument},
+		{Code: ErrCodeStorageAuthFailed, Category: sneterr.CategoryUnauthenticated, Status: http.StatusBadGateway},
+		{Code: ErrCodeStorageFull, Category: sneterr.CategoryResourceExhausted, Status: http.StatusInsufficientStorage, Retryable: true, Access: sneterr.AccessWrite},
+		{Code: ErrCodeStorageThrottled, Category: sneterr.CategoryResourceExhausted, Status: http.StatusServiceUnavailable, Retryable: true},
+		{Code: ErrCodeStorageUnavailable, Category: sneterr.CategoryUnavailable, Retryable: true},
+		{Code: ErrCodeTransferFailed, Category: sneterr.CategoryInternal, Status: http.StatusBadGateway},
+	} {
+		info.Fields = schema
+		sneterr.Register(info)
+	}
+}
+
+// messages are the messages of the errors, which do not include the reply
+// of the remote side.
+var messages = map[string]string{
+	ErrCodeFileNotFound:       "the file does not exist",
+	ErrCodeFileAccessDenied:   "access to the file was denied",
+	ErrCodeFileConflict:       "the file conflicts with its current state",
+	ErrCodeInvalidFileRequest: "the file request is invalid",
+	ErrCodeStorageAuthFailed:  "authentication with the storage failed",
+	ErrCodeStorageFull:        "the storage is full",
+	ErrCodeStorageThrottled:   "the storage is throttling requests",
+	ErrCodeStorageUnavailable: "the storage is unavailable",
+	ErrCodeTransferFailed:     "the transfer failed",
+}
+
+// newError returns the error with code for a failure reported by the
+// remote side of protocol with remoteCode.
+func newError(code, protocol, remoteCode string, cause error) sneterr.Error {
+	sneterr.Helper()
+
+	opts := []sneterr.Option{
+		sneterr.WithCause(cause),
+		sneterr.WithField(FieldProtocol, protocol),
+		sneterr.WithField(FieldRemoteCode, remoteCode),
+	}
+	if d, ok := RetryAfter[code]; ok {
+		opts = append(opts, sneterr.WithField(FieldRetryAfter, d))
+	}
+	return sneterr.New(code, messages[code], opts...)
+}
+
+// passThrough returns err, which is not a transfer failure, as an Error.
+func passThrough(err error) sneterr.Error {
+	sneterr.Helper()
+
+	if e, ok := err.(sneterr.Error); ok {
+		return e
+	}
+	return sneterr.Wrap(err, sneterr.ErrCodeUnknown, err.Error())
+}
+
+// RetryAfterOf returns the delay to wait before retrying the transfer
+// which failed with err, if its error has a retry hint.
+func RetryAfterOf(err error) (time.Duration, bool) {
+	return sneterr.Field[time.Duration](err, FieldRetryAfter)
+}
@@ -0,0 +1,132 @@
+package sneterr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Field keys used by WrapStage.
+const (
+	FieldStages  = "stages"
+	FieldRecords = "records"
+)
+
+// WrapStage returns err annotated with the pipeline stage and the record
+// being processed when it failed.
+//
+// Wrapping an error that already went through WrapStage accumulates the
+// stage and record, so an error bubbling up a pipeline keeps the full path,
+// innermost stage first. An empty record is not recorded. If err is nil
+// WrapStage returns nil.
+func WrapStage(err error, stage, record string) Error {
+	if err == nil {
+		return nil
+	}
+
+	stages := append(Stages(err), stage)
+	records := Records(err)
+	if record != "" {
+		records = append(records, record)
+	}
+	return WithFields(err, Fields{FieldStages: stages, FieldRecords: records})
+}
+
+// Stages returns the pipeline stages err went through, innermost first.
+func Stages(err error) []string {
+	return stringsField(err, FieldStages)
+}
+
+// Records returns the record identifiers attached to err by WrapStage.
+func Records(err error) []string {
+	return stringsField(err, FieldRecords)
+}
+
+// stringsField returns a copy of the []string field key of err.
+func stringsField(err error, key string) []string {
+	v, _ := FieldsOf(err)[key].([]string)
+	if len(v) == 0 {
+		return nil
+	}
+	return append([]string(nil), v...)
+}
+
+// A StageCount is the number of failures of a job with the same code in
+// the same stage.
+type StageCount struct {
+	Code  string `json:"code"`
+	Stage string `json:"stage"`
+	Count int    `json:"count"`
+}
+
+// A JobReport summarizes the outcome of a job.
+type JobReport struct {
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Counts    []StageCount `json:"counts,omitempty"`
+}
+
+// String returns a short human readable form of the report.
+func (r JobReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "succeeded=%d failed=%d", r.Succeeded, r.Failed)
+	for _, c := range r.Counts {
+		fmt.Fprintf(&sb, "\n\t%s %s: %d", c.Stage, c.Code, c.Count)
+	}
+	return sb.String()
+}
+
+// A JobSummary builds a JobReport from the outcome of each record processed
+// by a job. Failures are counted by code and by the stage they originated
+// in. The zero JobSummary is ready to use and safe for concurrent use.
+type JobSummary struct {
+	mu        sync.Mutex
+	succeeded int
+	failed    int
+	counts    map[StageCount]int
+}
+
+// Add records the outcome of one record. A nil err counts as a success.
+func (s *JobSummary) Add(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.succeeded++
+		return
+	}
+	s.failed++
+
+	key := StageCount{Code: ErrCodeUnknown}
+	if e, ok := err.(Error); ok {
+		key.Code = e.Code()
+	}
+	if stages := Stages(err); len(stages) > 0 {
+		key.Stage = stages[0]
+	}
+	if s.counts == nil {
+		s.counts = map[StageCount]int{}
+	}
+	s.counts[key]++
+}
+
+// Report returns the summary of the outcomes added so far. Counts are
+// ordered by stage and code.
+func (s *JobSummary) Report() JobReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := JobReport{Succeeded: s.succeeded, Failed: s.failed}
+	for key, n := range s.counts {
+		key.Count = n
+		r.Counts = append(r.Counts, key)
+	}
+	sort.Slice(r.Counts, func(i, j int) bool {
+		if r.Counts[i].Stage != r.Counts[j].Stage {
+			return r.Counts[i].Stage < r.Counts[j].Stage
+		}
+		return r.Counts[i].Code < r.Counts[j].Code
+	})
+	return r
+}
@@ -0,0 +1,38 @@
+package sneterr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// HTTPStatus returns the HTTP status code of a response reporting err.
+//
+// An error implementing HTTPStatus() int chooses its own status. Otherwise
+// the status registered for the error's code is used, then the status of
+// its category. A nil error has http.StatusOK.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var s interface{ HTTPStatus() int }
+	if errors.As(err, &s) {
+		if status := s.HTTPStatus(); status != 0 {
+			return status
+		}
+	}
+
+	var e Error
+	if errors.As(err, &e) {
+		if info, ok := Lookup(e.Code()); ok && info.Status != 0 {
+			return info.Status
+		}
+	}
+	return CategoryOf(err).HTTPStatus()
+}
+
+// HTTPStatus returns the HTTP status chosen for the error, or zero if the
+// status registered for its code applies.
+func (b baseError) HTTPStatus() int {
+	return b.status
+}
@@ -0,0 +1,136 @@
+package sneterr
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FieldResource is the field key of the resource affected by an error,
+// such as "orders/o-1234", set by WithResource and reported by Summarize.
+const FieldResource = "resource"
+
+// WithResource records in the FieldResource field the resource affected
+// by the error.
+func WithResource(resource string) Option {
+	return WithField(FieldResource, resource)
+}
+
+// A Summary is a concise description of an error for ops assistants and
+// incident tooling, see Summarize.
+type Summary struct {
+	// Code of the first Error in the chain.
+	Code string `json:"code"`
+
+	// Category and severity of the error.
+	Category Category `json:"category"`
+	Severity string   `json:"severity"`
+
+	// The message of the error, or the text of its HTTP status for
+	// server errors.
+	Message string `json:"message"`
+
+	// The resource affected, from the FieldResource field.
+	Resource string `json:"resource,omitempty"`
+
+	// Whether the failed operation can be retried.
+	Retryable bool `json:"retryable"`
+
+	// The suggested remediation and links registered for the code.
+	Remediation string   `json:"remediation,omitempty"`
+	Links       []string `json:"links,omitempty"`
+
+	// The codes of the other errors in the chain and of the errors
+	// grouped by a MultiError, each listed once.
+	Related []string `json:"related,omitempty"`
+
+	// Fingerprint of the error, to correlate the summary with logs.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Summarize returns a Summary of err, which is safe to hand to tools
+// outside the service such as an ops chatbot: it holds the classification
+// of the error, the remediation and links registered in the catalog for
+// its code, and no internals. Stacks, locations, fields other than
+// FieldResource and the messages of causes are left out, as is the
+// message of errors with a server error status, like sneterrhttp does in
+// responses; the resource has its URL redacted if it is one, see
+// SetURLRedactor. If err is nil Summarize returns the zero Summary.
+func Summarize(err error) Summary {
+	if err == nil {
+		return Summary{}
+	}
+
+	status := HTTPStatus(err)
+	s := Summary{
+		Code:        CodeOf(err),
+		Category:    CategoryOf(err),
+		Severity:    SeverityOf(err).String(),
+		Message:     http.StatusText(status),
+		Retryable:   Retryable(err),
+		Fingerprint: Fingerprint(err),
+	}
+	if status < http.StatusInternalServerError {
+		s.Message = Localize(err)
+	}
+	if resource, ok := Field[string](err, FieldResource); ok {
+		s.Resource = redactResource(resource)
+	}
+	if info, ok := Lookup(s.Code); ok {
+		s.Remediation = info.Remediation
+		s.Links = append([]string(nil), info.Links...)
+	}
+
+	seen := map[string]bool{s.Code: true}
+	walk(err, func(e error) bool {
+		if se, ok := e.(Error); ok && !seen[se.Code()] {
+			seen[se.Code()] = true
+			s.Related = append(s.Related, se.Code())
+		}
+		return true
+	})
+	return s
+}
+
+// String returns the summary as a few lines of text.
+func (s Summary) String() string {
+	var sb strings.Builder
+	sb.WriteString(SprintError(s.Code, s.Message, "", nil))
+	sb.WriteString("\ncategory: ")
+	sb.WriteString(string(s.Category))
+	sb.WriteString(", severity: ")
+	sb.WriteString(s.Severity)
+	if s.Retryable {
+		sb.WriteString(", retryable")
+	}
+	if s.Resource != "" {
+		sb.WriteString("\nresource: ")
+		sb.WriteString(s.Resource)
+	}
+	if s.Remediation != "" {
+		sb.WriteString("\nremediation: ")
+		sb.WriteString(s.Remediation)
+	}
+	for _, l := range s.Links {
+		sb.WriteString("\nsee: ")
+		sb.WriteString(l)
+	}
+	if len(s.Related) > 0 {
+		sb.WriteString("\nrelated: ")
+		sb.WriteString(strings.Join(s.Related, ", "))
+	}
+	return sb.String()
+}
+
+// redactResource returns resource with its URL redacted if it is an
+// absolute URL, as it may carry credentials.
+func redactResource(resource string) string {
+	u, err := url.Parse(resource)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return resource
+	}
+	if s, ok := redactField(u).(string); ok {
+		return s
+	}
+	return resource
+}
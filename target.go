@@ -0,0 +1,37 @@
+package sneterr
+
+// codeTarget is the target of errors.Is for the errors with a code.
+type codeTarget string
+
+// Error returns a description of the target.
+func (t codeTarget) Error() string {
+	return "sneterr: errors with code " + string(t)
+}
+
+// Target returns a value for errors.Is matching the errors with code, so
+// errors can be compared with sentinel values instead of their codes:
+//
+//	var ErrOrderNotFound = sneterr.Target(ErrCodeOrderNotFound)
+//
+//	if errors.Is(err, ErrOrderNotFound) {
+//
+// errors.Is reports a match when any Error of this package in the chain of
+// err has code, including the errors grouped by a MultiError. Targets for
+// the same code are equal. sneterrgen generates a target for each code of
+// a catalog. Targets are not meant to be returned as errors.
+func Target(code string) error {
+	return codeTarget(code)
+}
+
+// Is reports whether target is the Target of the code of b.
+func (b baseError) Is(target error) bool {
+	t, ok := target.(codeTarget)
+	return ok && string(t) == b.code
+}
+
+// Is reports whether target is the Target of the code of m. The grouped
+// errors are matched by errors.Is through Unwrap.
+func (m multiError) Is(target error) bool {
+	t, ok := target.(codeTarget)
+	return ok && string(t) == m.code
+}
@@ -0,0 +1,205 @@
+package sneterr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// templateKey identifies the template of a code in a language. The
+// default template has an empty language.
+type templateKey struct {
+	lang string
+	code string
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   = map[templateKey]*template.Template{}
+)
+
+// Template declares the message template used by NewT for code. The text
+// uses the text/template syntax, with the params given to NewT as data:
+//
+//	sneterr.Template("OrderNotFound",
+//		"order {{.order_id}} was not found for customer {{.customer_id}}")
+//
+// This default template should be written in the language of the
+// developers: it renders the message of the error, which is what logs
+// show. Translations for the clients are declared with LocalizedTemplate.
+//
+// Template panics if text cannot be parsed, so it is meant to be called
+// during package initialization.
+func Template(code, text string) {
+	LocalizedTemplate("", code, text)
+}
+
+// LocalizedTemplate declares the message template of code in the language
+// lang, a BCP 47 tag such as "pt-BR", used by Localize.
+//
+// LocalizedTemplate panics if text cannot be parsed, so it is meant to be
+// called during package initialization.
+func LocalizedTemplate(lang, code, text string) {
+	t := template.Must(template.New(code).Option("missingkey=error").Parse(text))
+
+	templatesMu.Lock()
+	templates[templateKey{strings.ToLower(lang), code}] = t
+	templatesMu.Unlock()
+}
+
+// NewT returns an Error for code whose message is rendered from the
+// template declared for the code. The params are also attached to the
+// error as fields.
+//
+// params is either a map with string keys or a struct, in which case its
+// exported fields are used, named after their sneterr struct tag if they
+// have one. The template refers to the fields by those names:
+//
+//	type orderParams struct {
+//		OrderID    string `sneterr:"order_id"`
+//		CustomerID string `sneterr:"customer_id"`
+//	}
+//
+//	err := sneterr.NewT("OrderNotFound", orderParams{OrderID: id, CustomerID: cid})
+//
+// If no template was declared for code, or it fails to render, such as
+// when it refers to a missing param, the message is the code itself and
+// the failure is recorded in the FieldTemplateError field, which hooks and
+// logs show. Fields attached by opts are available to the template too, as
+// they are to its translations rendered by Localize.
+func NewT(code string, params interface{}, opts ...Option) Error {
+	fields := paramFields(params)
+	checkFields(code, fields)
+	b := newBaseError(code, "", nil, "", 0)
+	b.fields = fields
+	b.apply(opts)
+
+	msg, err := renderTemplate("", code, b.fields)
+	b.message = msg
+	b.templated = err == nil
+	if err != nil {
+		b.message = code
+		if b.fields == nil {
+			b.fields = Fields{}
+		}
+		b.fields[FieldTemplateError] = err.Error()
+	}
+	return finishError(2, b)
+}
+
+// FieldTemplateError is the field key of the reason NewT could not render
+// the message of an error from its template.
+const FieldTemplateError = "template_error"
+
+// renderTemplate executes the template declared for code in lang with
+// fields. It fails if there is no such template or it fails to render.
+func renderTemplate(lang, code string, fields Fields) (string, error) {
+	templatesMu.RLock()
+	t, ok := templates[templateKey{lang, code}]
+	templatesMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("no template declared for code %s", code)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, map[string]interface{}(fields)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Localize returns the message of the first Error in the chain of err in
+// the first of langs it was translated to with LocalizedTemplate. A tag
+// with a region, such as "pt-BR", falls back to its base language "pt".
+// The message of the error is returned when none of langs is available,
+// including for errors not created by NewT.
+func Localize(err error, langs ...string) string {
+	var e Error
+	if !errors.As(err, &e) {
+		if err == nil {
+			return ""
+		}
+		return err.Error()
+	}
+
+	b, ok := e.(*baseError)
+	if !ok || !b.templated {
+		return e.Message()
+	}
+	for _, lang := range langs {
+		if msg, ok := b.localize(lang); ok {
+			return msg
+		}
+	}
+	return b.Message()
+}
+
+// localize renders the message of b in lang, or in its base language.
+func (b *baseError) localize(lang string) (string, bool) {
+	lang = strings.ToLower(lang)
+	for lang != "" {
+		if msg, err := renderTemplate(lang, b.code, b.fields); err == nil {
+			return msg, true
+		}
+		i := strings.LastIndexByte(lang, '-')
+		if i < 0 {
+			break
+		}
+		lang = lang[:i]
+	}
+	return "", false
+}
+
+// paramFields converts template params into Fields, redacted like with
+// WithField.
+func paramFields(params interface{}) Fields {
+	switch p := params.(type) {
+	case nil:
+		return nil
+	case Fields:
+		return redactFields(p)
+	case map[string]interface{}:
+		return redactFields(Fields(p))
+	}
+
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	fields := Fields{}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for iter := v.MapRange(); iter.Next(); {
+			fields[iter.Key().String()] = redactField(iter.Value().Interface())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("sneterr"); ok {
+				if tag == "-" {
+					continue
+				}
+				name = tag
+			}
+			fields[name] = redactField(v.Field(i).Interface())
+		}
+	default:
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+package sneterr
+
+import "testing"
+
+func TestNewT(t *testing.T) {
+	Template("TemplateTestOrderNotFound", "order {{.order_id}} was not found for customer {{.customer_id}}")
+
+	type orderParams struct {
+		OrderID    string `sneterr:"order_id"`
+		CustomerID string `sneterr:"customer_id"`
+	}
+
+	tests := []struct {
+		name      string
+		code      string
+		params    interface{}
+		opts      []Option
+		message   string
+		failed    bool
+		templated bool
+	}{
+		{
+			name:      "struct",
+			code:      "TemplateTestOrderNotFound",
+			params:    orderParams{OrderID: "o-1", CustomerID: "c-2"},
+			message:   "order o-1 was not found for customer c-2",
+			templated: true,
+		},
+		{
+			name:      "map",
+			code:      "TemplateTestOrderNotFound",
+			params:    Fields{"order_id": "o-1", "customer_id": "c-2"},
+			message:   "order o-1 was not found for customer c-2",
+			templated: true,
+		},
+		{
+			name:      "option field",
+			code:      "TemplateTestOrderNotFound",
+			params:    Fields{"order_id": "o-1"},
+			opts:      []Option{WithField("customer_id", "c-2")},
+			message:   "order o-1 was not found for customer c-2",
+			templated: true,
+		},
+		{
+			name:    "missing param",
+			code:    "TemplateTestOrderNotFound",
+			params:  Fields{"OrderID": "o-1"},
+			message: "TemplateTestOrderNotFound",
+			failed:  true,
+		},
+		{
+			name:    "no template",
+			code:    "TemplateTestUndeclared",
+			params:  nil,
+			message: "TemplateTestUndeclared",
+			failed:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewT(tt.code, tt.params, tt.opts...)
+			if got := err.Message(); got != tt.message {
+				t.Errorf("Message() = %q, want %q", got, tt.message)
+			}
+			reason, failed := Field[string](err, FieldTemplateError)
+			if failed != tt.failed || (failed && reason == "") {
+				t.Errorf("field %s = %q, %v, want set %v", FieldTemplateError, reason, failed, tt.failed)
+			}
+			if got := err.(*baseError).templated; got != tt.templated {
+				t.Errorf("templated = %v, want %v", got, tt.templated)
+			}
+		})
+	}
+}
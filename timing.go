@@ -0,0 +1,56 @@
+package sneterr
+
+import (
+	"errors"
+	"time"
+)
+
+// OccurredAt returns when the error was created.
+func (b baseError) OccurredAt() time.Time {
+	return b.time
+}
+
+// Duration returns how long the failed operation took, or zero if it was
+// not recorded.
+func (b baseError) Duration() time.Duration {
+	return b.duration
+}
+
+// WithDuration returns a copy of err recording that the failed operation
+// took d. If err is nil WithDuration returns nil.
+func WithDuration(err error, d time.Duration) Error {
+	if err == nil {
+		return nil
+	}
+	b := derive(err)
+	b.duration = d
+	return b
+}
+
+// OccurredAt returns when the original failure of err happened, that is the
+// earliest creation time found in its chain. It returns the zero time if no
+// error of the chain recorded one.
+func OccurredAt(err error) time.Time {
+	var t time.Time
+	for ; err != nil; err = errors.Unwrap(err) {
+		o, ok := err.(interface{ OccurredAt() time.Time })
+		if !ok {
+			continue
+		}
+		if at := o.OccurredAt(); !at.IsZero() && (t.IsZero() || at.Before(t)) {
+			t = at
+		}
+	}
+	return t
+}
+
+// DurationOf returns the duration recorded closest to the top of the chain
+// of err, or zero if none was recorded.
+func DurationOf(err error) time.Duration {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if d, ok := err.(interface{ Duration() time.Duration }); ok && d.Duration() != 0 {
+			return d.Duration()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+package sneterr
+
+import (
+	"net/http"
+	"sort"
+)
+
+// ErrCodeInvalidTransition is the code of the errors returned by
+// NewInvalidTransition.
+const ErrCodeInvalidTransition = "InvalidTransition"
+
+// Field keys of the errors returned by NewInvalidTransition.
+const (
+	FieldEntity        = "entity"
+	FieldState         = "state"
+	FieldEvent         = "event"
+	FieldAllowedEvents = "allowed_events"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodeInvalidTransition,
+		Category: CategoryConflict,
+		Severity: SeverityInfo,
+		Status:   http.StatusConflict,
+		Fields: Schema{
+			FieldEntity:        FieldString,
+			FieldState:         FieldString,
+			FieldEvent:         FieldString,
+			FieldAllowedEvents: FieldAny,
+		},
+	})
+}
+
+// A Transition describes an event a state machine refused.
+type Transition struct {
+	// Type of the entity, such as "order".
+	Entity string
+
+	// State the entity was in.
+	State string
+
+	// Event attempted on the entity, such as "ship".
+	Event string
+
+	// Events allowed in State, sorted.
+	Allowed []string
+}
+
+// NewInvalidTransition returns an Error with ErrCodeInvalidTransition
+// reporting that event cannot be applied to an entity of type entity in
+// state, rendered with http.StatusConflict. The entity type, state, event
+// and the events allowed in state are attached as fields, so clients can
+// tell what they can do instead:
+//
+//	return sneterr.NewInvalidTransition("order", o.State, "ship",
+//		orderMachine.Events(o.State))
+func NewInvalidTransition(entity, state, event string, allowed []string, opts ...Option) Error {
+	allowed = append([]string{}, allowed...)
+	sort.Strings(allowed)
+
+	b := newBaseError(ErrCodeInvalidTransition,
+		"cannot "+event+" "+entity+" in state "+state, nil, "", 0)
+	b.status = http.StatusConflict
+	b.fields = Fields{
+		FieldEntity:        entity,
+		FieldState:         state,
+		FieldEvent:         event,
+		FieldAllowedEvents: allowed,
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// InvalidTransitionOf returns the transition reported by the first error
+// with ErrCodeInvalidTransition in the chain of err.
+func InvalidTransitionOf(err error) (Transition, bool) {
+	var t Transition
+	found := false
+	walk(err, func(e error) bool {
+		se, ok := e.(Error)
+		if !ok || se.Code() != ErrCodeInvalidTransition {
+			return true
+		}
+		found = true
+		fields := matchedFields(se)
+		t.Entity, _ = fields[FieldEntity].(string)
+		t.State, _ = fields[FieldState].(string)
+		t.Event, _ = fields[FieldEvent].(string)
+		switch v := fields[FieldAllowedEvents].(type) {
+		case []string:
+			t.Allowed = v
+		case []interface{}:
+			for _, a := range v {
+				if s, ok := a.(string); ok {
+					t.Allowed = append(t.Allowed, s)
+				}
+			}
+		}
+		return false
+	})
+	return t, found
+}
@@ -0,0 +1,85 @@
+package sneterr
+
+// ErrCodeIncompatibleVersion is the code of the errors returned by
+// NewIncompatibleVersion.
+const ErrCodeIncompatibleVersion = "IncompatibleVersion"
+
+// Field keys of the errors returned by NewIncompatibleVersion.
+const (
+	FieldComponent    = "component"
+	FieldPeerVersion  = "peer_version"
+	FieldLocalVersion = "local_version"
+
+	// Always true: retrying is pointless until one side is upgraded.
+	FieldRetryAfterUpgrade = "retry_after_upgrade"
+)
+
+func init() {
+	Register(CodeInfo{
+		Code:     ErrCodeIncompatibleVersion,
+		Category: CategoryPreconditionFailed,
+		Severity: SeverityWarn,
+		Fields: Schema{
+			FieldComponent:         FieldString,
+			FieldPeerVersion:       FieldString,
+			FieldLocalVersion:      FieldString,
+			FieldRetryAfterUpgrade: FieldBool,
+		},
+	})
+}
+
+// A VersionSkew describes versions of a peer and of the local process
+// which cannot work together.
+type VersionSkew struct {
+	// What is versioned, such as "schema" or "orders.v1 proto".
+	Component string
+
+	// Version reported by the peer.
+	Peer string
+
+	// Version of the local process.
+	Local string
+}
+
+// NewIncompatibleVersion returns an Error with ErrCodeIncompatibleVersion
+// reporting that the peer uses version peer of component, which the local
+// process at version local does not support. Such errors happen during
+// rolling deployments, until both sides run compatible versions: they are
+// not retryable, which the FieldRetryAfterUpgrade field spells out for
+// clients.
+//
+//	if v := req.Header.Get("Schema-Version"); v != schemaVersion {
+//		return sneterr.NewIncompatibleVersion("schema", v, schemaVersion)
+//	}
+func NewIncompatibleVersion(component, peer, local string, opts ...Option) Error {
+	b := newBaseError(ErrCodeIncompatibleVersion,
+		component+" version "+peer+" is incompatible with version "+local, nil, "", 0)
+	b.fields = Fields{
+		FieldComponent:         component,
+		FieldPeerVersion:       peer,
+		FieldLocalVersion:      local,
+		FieldRetryAfterUpgrade: true,
+	}
+	b.apply(opts)
+	return finishError(2, b)
+}
+
+// IncompatibleVersionOf returns the version skew reported by the first
+// error with ErrCodeIncompatibleVersion in the chain of err.
+func IncompatibleVersionOf(err error) (VersionSkew, bool) {
+	var v VersionSkew
+	found := false
+	walk(err, func(e error) bool {
+		se, ok := e.(Error)
+		if !ok || se.Code() != ErrCodeIncompatibleVersion {
+			return true
+		}
+		found = true
+		fields := matchedFields(se)
+		v.Component, _ = fields[FieldComponent].(string)
+		v.Peer, _ = fields[FieldPeerVersion].(string)
+		v.Local, _ = fields[FieldLocalVersion].(string)
+		return false
+	})
+	return v, found
+}
@@ -0,0 +1,113 @@
+package sneterr
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+)
+
+// A Warning reports a condition worth surfacing to the caller which is not
+// a failure, such as a deprecation notice or a partial result. It carries a
+// code, message and fields like an Error, but does not satisfy the error
+// interface.
+type Warning struct {
+	// Short phrase depicting the classification of the warning.
+	Code string `json:"code"`
+
+	// The warning details message.
+	Message string `json:"message"`
+
+	// Optional structured data describing the warning.
+	Fields Fields `json:"fields,omitempty"`
+}
+
+// NewWarning returns a Warning for the code, message and fields. URL and
+// request fields are redacted like those of errors, see WithField.
+func NewWarning(code, message string, fields Fields) Warning {
+	return Warning{Code: code, Message: message, Fields: redactFields(fields)}
+}
+
+// String returns the string representation of the warning.
+func (w Warning) String() string {
+	return SprintError(w.Code, w.Message, "", nil)
+}
+
+// LogValue returns the warning as a slog group of its code, message and
+// fields.
+func (w Warning) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", w.Code),
+		slog.String("message", w.Message),
+	}
+	if len(w.Fields) > 0 {
+		attrs = append(attrs, slog.Any("fields", map[string]interface{}(w.Fields)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Warnings collects the warnings raised while serving a request. The zero
+// Warnings is ready to use and safe for concurrent use.
+type Warnings struct {
+	mu   sync.Mutex
+	list []Warning
+}
+
+// Add appends w to the collected warnings.
+func (ws *Warnings) Add(w Warning) {
+	ws.mu.Lock()
+	ws.list = append(ws.list, w)
+	ws.mu.Unlock()
+}
+
+// List returns a copy of the collected warnings, in the order they were
+// added.
+func (ws *Warnings) List() []Warning {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return append([]Warning(nil), ws.list...)
+}
+
+// Log writes every collected warning to logger at the Warn level.
+func (ws *Warnings) Log(ctx context.Context, logger *slog.Logger) {
+	for _, w := range ws.List() {
+		logger.LogAttrs(ctx, slog.LevelWarn, w.Message, slog.Any("warning", w))
+	}
+}
+
+// MarshalJSON encodes the collected warnings as a JSON array, suitable for
+// the "warnings" member of a response body.
+func (ws *Warnings) MarshalJSON() ([]byte, error) {
+	list := ws.List()
+	if list == nil {
+		list = []Warning{}
+	}
+	return json.Marshal(list)
+}
+
+type warningsKey struct{}
+
+// ContextWithWarnings returns a copy of ctx carrying a new Warnings
+// collector, along with the collector.
+func ContextWithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	ws := &Warnings{}
+	return context.WithValue(ctx, warningsKey{}, ws), ws
+}
+
+// WarningsFromContext returns the Warnings collector carried by ctx, or nil
+// if there is none.
+func WarningsFromContext(ctx context.Context) *Warnings {
+	ws, _ := ctx.Value(warningsKey{}).(*Warnings)
+	return ws
+}
+
+// AddWarning adds w to the Warnings collector carried by ctx. It reports
+// whether ctx carried a collector.
+func AddWarning(ctx context.Context, w Warning) bool {
+	ws := WarningsFromContext(ctx)
+	if ws == nil {
+		return false
+	}
+	ws.Add(w)
+	return true
+}